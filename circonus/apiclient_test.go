@@ -0,0 +1,581 @@
+package circonus
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+)
+
+// The vendored apiclient ships without its tests, the behavior the provider
+// relies on is covered here against a test server.
+
+// testAPIRecordQueries returns a handler recording the raw query of every
+// request and responding with body.
+func testAPIRecordQueries(queries *[]string, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestAPISearchFilterEncoding(t *testing.T) {
+	search := api.SearchQueryType("(active:1)")
+	tests := []struct {
+		search *api.SearchQueryType
+		filter *api.SearchFilterType
+		query  string
+	}{
+		{nil, nil, ""},
+		{nil, &api.SearchFilterType{}, ""},
+		{nil, &api.SearchFilterType{"f_type": []string{"http"}}, "f_type=http"},
+		{
+			nil,
+			&api.SearchFilterType{"f_tags_has": []string{"env:prod", "role:web"}},
+			"f_tags_has=env%3Aprod&f_tags_has=role%3Aweb",
+		},
+		{
+			&search,
+			&api.SearchFilterType{
+				"f_type":     []string{"http", "json"},
+				"f_tags_has": []string{"role:web", "env:prod"},
+			},
+			"f_tags_has=role%3Aweb&f_tags_has=env%3Aprod&f_type=http&f_type=json&search=%28active%3A1%29",
+		},
+	}
+
+	for _, test := range tests {
+		var queries []string
+		ctxt, srv := testAPIProviderContext(t, testAPIRecordQueries(&queries, `[]`))
+		_, err := ctxt.client.SearchCheckBundles(test.search, test.filter)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.filter, err)
+		}
+
+		if len(queries) != 1 || queries[0] != test.query {
+			t.Errorf("%v: expected query %q, got %q", test.filter, test.query, queries)
+		}
+	}
+}
+
+func TestAPISearchFilterDeterministic(t *testing.T) {
+	keys := []string{"f_type", "f_tags_has", "f_target", "f_name", "f_tags_not"}
+
+	var queries []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordQueries(&queries, `[]`))
+	defer srv.Close()
+
+	// the same filters, inserted in a different order every time
+	for i := range keys {
+		filter := api.SearchFilterType{}
+		for j := range keys {
+			key := keys[(i+j)%len(keys)]
+			filter[key] = []string{key + ":a", key + ":b"}
+		}
+
+		if _, err := ctxt.client.SearchCheckBundles(nil, &filter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, query := range queries[1:] {
+		if query != queries[0] {
+			t.Errorf("expected the same query for the same filters, got %q and %q", queries[0], query)
+		}
+	}
+}
+
+// testAPIRecordBodies returns a handler recording the body of every request
+// and responding with body.
+func testAPIRecordBodies(bodies *[]string, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestAPIReadOnlyFieldsNotSent(t *testing.T) {
+	var bodies []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordBodies(&bodies, `{}`))
+	defer srv.Close()
+
+	m := api.Maintenance{
+		CID:            "/maintenance/1",
+		Item:           "/check/1",
+		Type:           "check",
+		Created:        1600000000,
+		LastModified:   1600000001,
+		LastModifiedBy: "/user/1",
+		Start:          1700000000,
+		Stop:           1700003600,
+	}
+	if _, err := ctxt.client.CreateMaintenanceWindow(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ctxt.client.UpdateMaintenanceWindow(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u := api.User{
+		CID:            "/user/1",
+		Email:          "user@example.com",
+		LastModified:   1600000001,
+		LastModifiedBy: "/user/2",
+	}
+	if _, err := ctxt.client.UpdateUser(&u); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, body := range bodies {
+		for _, field := range []string{"_created", "_last_modified", "_last_modified_by"} {
+			if strings.Contains(body, `"`+field+`"`) {
+				t.Errorf("expected %s not to be sent, got %s", field, body)
+			}
+		}
+	}
+
+	// the fields of the passed objects are left untouched
+	if m.LastModified == 0 || u.LastModified == 0 {
+		t.Error("expected the passed objects to keep their _last_modified")
+	}
+}
+
+func TestAPIRateLimitRetries(t *testing.T) {
+	var attempts []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client, err := api.NewAPI(&api.Config{
+		URL:           srv.URL,
+		TokenKey:      "test-token",
+		MinRetryDelay: "1ms",
+		MaxRetryDelay: "1ms",
+		RateLimit:     10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Get("/user/current"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the retries are rate limited too, not only the first attempt
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+	if d := attempts[2].Sub(attempts[0]); d < 150*time.Millisecond {
+		t.Errorf("expected the retries to wait on the rate limit, took %s", d)
+	}
+}
+
+func TestAPISearchTagPrefixAndRegex(t *testing.T) {
+	filter := api.SearchFilterType{}
+	for _, prefix := range []string{"prod", ""} {
+		if err := filter.ByTagPrefix("Env", prefix); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, re := range []string{"prod|stag", ".*-east"} {
+		if err := filter.ByTagRegex("dc.region", re); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var queries []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordQueries(&queries, `[]`))
+	defer srv.Close()
+
+	if _, err := ctxt.client.SearchCheckBundles(nil, &filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(queries))
+	}
+
+	values, err := url.ParseQuery(queries[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := url.Values{
+		"f_tags_wildcard": []string{"env:prod*", "env:*"},
+		"f_tags_regex":    []string{`^dc\.region:(?:prod|stag)`, `^dc\.region:(?:.*-east)`},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected query %v, got %v", expected, values)
+	}
+
+	invalid := []struct {
+		category string
+		value    string
+		regex    bool
+	}{
+		{"", "prod", false},
+		{"env:prod", "", false},
+		{"env*", "", false},
+		{"env", "prod*", false},
+		{"", "prod", true},
+		{"env:prod", "", true},
+		{"env", "(prod", true},
+	}
+	for _, test := range invalid {
+		filter := api.SearchFilterType{}
+		var err error
+		if test.regex {
+			err = filter.ByTagRegex(test.category, test.value)
+		} else {
+			err = filter.ByTagPrefix(test.category, test.value)
+		}
+		if err == nil {
+			t.Errorf("%q %q (regex %t): expected an error", test.category, test.value, test.regex)
+		}
+		if len(filter) != 0 {
+			t.Errorf("%q %q (regex %t): expected no filter to be added, got %v", test.category, test.value, test.regex, filter)
+		}
+	}
+}
+
+func TestAPIMetricFilter(t *testing.T) {
+	filter, err := api.MetricFilter("numeric", "Env:Prod", "role:web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var queries []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordQueries(&queries, `[{
+		"_cid": "/metric/1_cpu",
+		"_check": "/check/1",
+		"_metric_name": "cpu",
+		"_metric_type": "numeric",
+		"_check_tags": ["env:prod", "role:web"]
+	}]`))
+	defer srv.Close()
+
+	metrics, err := ctxt.client.SearchMetrics(nil, &filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(queries))
+	}
+
+	values, err := url.ParseQuery(queries[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := url.Values{
+		"f__metric_type":    []string{"numeric"},
+		"f__check_tags_has": []string{"env:prod", "role:web"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected query %v, got %v", expected, values)
+	}
+
+	if len(*metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %d", len(*metrics))
+	}
+	if m := (*metrics)[0]; m.CheckCID != "/check/1" || m.MetricName != "cpu" || m.MetricType != "numeric" || len(m.CheckTags) != 2 {
+		t.Errorf("unexpected metric %+v", m)
+	}
+
+	// an empty type and no tags match every metric
+	if filter, err := api.MetricFilter(""); err != nil || len(filter) != 0 {
+		t.Errorf("expected an empty filter, got %v (%v)", filter, err)
+	}
+
+	invalid := []struct {
+		metricType string
+		tags       []string
+	}{
+		{"counter", nil},
+		{"numeric", []string{"prod"}},
+		{"", []string{"env:prod", "env:"}},
+	}
+	for _, test := range invalid {
+		if _, err := api.MetricFilter(test.metricType, test.tags...); err == nil {
+			t.Errorf("%q %v: expected an error", test.metricType, test.tags)
+		}
+	}
+}
+
+func TestAPIMaintenanceTags(t *testing.T) {
+	m := api.Maintenance{
+		CID:  "/maintenance/1",
+		Item: "/check/1",
+		Type: "check",
+		Tags: []string{"env:prod"},
+	}
+
+	if err := m.AddTag("role:web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.AddTag("role:web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.AddTag("web"); err == nil {
+		t.Error("expected an error adding a tag without a category")
+	}
+	if !reflect.DeepEqual(m.Tags, []string{"env:prod", "role:web"}) {
+		t.Errorf("expected the tag to be added once, got %v", m.Tags)
+	}
+
+	if !m.HasTag("env:prod") || m.HasTag("env:dev") {
+		t.Errorf("unexpected HasTag results for %v", m.Tags)
+	}
+	if !m.RemoveTag("env:prod") || m.RemoveTag("env:prod") {
+		t.Errorf("expected env:prod to be removed once, got %v", m.Tags)
+	}
+	if m.HasTag("env:prod") {
+		t.Errorf("expected env:prod to be removed, got %v", m.Tags)
+	}
+
+	var nilWindow *api.Maintenance
+	if nilWindow.HasTag("env:prod") {
+		t.Error("expected a nil window to have no tags")
+	}
+
+	// the helpers only change the window, the update sends the tags
+	var bodies []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordBodies(&bodies, `{"_cid": "/maintenance/1"}`))
+	defer srv.Close()
+
+	if len(bodies) != 0 {
+		t.Fatalf("expected no request before the update, got %v", bodies)
+	}
+	if _, err := ctxt.client.UpdateMaintenanceWindow(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"tags":["role:web"]`) {
+		t.Errorf(`expected the update to send "tags":["role:web"], got %v`, bodies)
+	}
+}
+
+func TestAPIRemoveUserFromAllAccounts(t *testing.T) {
+	accounts := map[string]string{
+		"/account/1": `{"_cid": "/account/1", "users": [{"user": "/user/1", "role": "Admin"}, {"user": "/user/2", "role": "Admin"}]}`,
+		"/account/2": `{"_cid": "/account/2", "users": [{"user": "/user/2", "role": "Admin"}]}`,
+		"/account/4": `{"_cid": "/account/4", "users": [{"user": "/user/1", "role": "Normal"}]}`,
+	}
+	var updates []string
+	ctxt, srv := testAPIProviderContext(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/user/1":
+			_, _ = w.Write([]byte(`{"_cid": "/user/1", "accounts": [
+				{"account": "/account/1", "role": "Admin"},
+				{"account": "/account/2", "role": "Admin"},
+				{"account": "/account/2", "role": "Admin"},
+				{"account": "/account/3", "role": "Admin"},
+				{"account": "/account/4", "role": "Normal"}
+			]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/account/4":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code": "Forbidden"}`))
+		case r.Method == http.MethodPut:
+			b, _ := ioutil.ReadAll(r.Body)
+			updates = append(updates, r.URL.Path+" "+string(b))
+			_, _ = w.Write(b)
+		case accounts[r.URL.Path] != "":
+			_, _ = w.Write([]byte(accounts[r.URL.Path]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": "NotFound"}`))
+		}
+	})
+	defer srv.Close()
+
+	results, err := ctxt.client.RemoveUserFromAllAccounts("/user/1")
+	if err == nil {
+		t.Fatal("expected an error for the account the user could not be removed from")
+	}
+	if !strings.Contains(err.Error(), "/account/4") || !strings.Contains(err.Error(), "1 of 4 accounts") {
+		t.Errorf("expected the error to list /account/4, got %v", err)
+	}
+
+	// the user is removed from 1, already absent from 2, and 3 is gone
+	if len(results) != 4 {
+		t.Fatalf("expected a result per account, got %v", results)
+	}
+	for _, accountCID := range []string{"/account/1", "/account/2", "/account/3"} {
+		if err, ok := results[accountCID]; !ok || err != nil {
+			t.Errorf("%s: expected a nil result, got %v (%t)", accountCID, err, ok)
+		}
+	}
+	if results["/account/4"] == nil {
+		t.Error("/account/4: expected an error")
+	}
+
+	if len(updates) != 1 || !strings.HasPrefix(updates[0], "/account/1 ") {
+		t.Fatalf("expected only /account/1 to be updated, got %v", updates)
+	}
+	if strings.Contains(updates[0], `"/user/1"`) || !strings.Contains(updates[0], `"/user/2"`) {
+		t.Errorf("expected only /user/1 to be removed, got %s", updates[0])
+	}
+
+	if _, err := ctxt.client.RemoveUserFromAllAccounts(""); err == nil {
+		t.Error("expected an error for an empty user CID")
+	}
+}
+
+func TestAPIExecuteCAQL(t *testing.T) {
+	var queries []string
+	ctxt, srv := testAPIProviderContext(t, testAPIRecordQueries(&queries, `{
+		"version": "DF4",
+		"head": {"count": 3, "start": 1600000000, "period": 60},
+		"meta": [
+			{"kind": "numeric", "label": "cpu.user"},
+			{"kind": "numeric", "label": "cpu.system"}
+		],
+		"data": [[1.5, null, 3], [0, 2, 4.25]]
+	}`))
+	defer srv.Close()
+
+	result, err := ctxt.client.ExecuteCAQL(`find("cpu.*") | rolling:mean(5m)`, 1600000000, 1600000180, 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(queries))
+	}
+
+	values, err := url.ParseQuery(queries[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := url.Values{
+		"query":  []string{`find("cpu.*") | rolling:mean(5m)`},
+		"format": []string{"DF4"},
+		"start":  []string{"1600000000"},
+		"end":    []string{"1600000180"},
+		"period": []string{"60"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected query %v, got %v", expected, values)
+	}
+	if result.Head.Count != 3 || result.Head.Period != 60 {
+		t.Errorf("unexpected head %+v", result.Head)
+	}
+
+	series, err := result.Series()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 2 || series[0].Label != "cpu.user" || series[1].Label != "cpu.system" || series[1].Kind != "numeric" {
+		t.Fatalf("unexpected series %+v", series)
+	}
+	// a period without data decodes as nil, not 0
+	expectedValues := [][]interface{}{{1.5, nil, 3.0}, {0.0, 2.0, 4.25}}
+	for i, s := range series {
+		if len(s.Values) != len(expectedValues[i]) {
+			t.Fatalf("%s: expected %v, got %d values", s.Label, expectedValues[i], len(s.Values))
+		}
+		for j, v := range s.Values {
+			switch want := expectedValues[i][j]; {
+			case want == nil && v != nil:
+				t.Errorf("%s[%d]: expected nil, got %v", s.Label, j, *v)
+			case want != nil && (v == nil || *v != want.(float64)):
+				t.Errorf("%s[%d]: expected %v, got %v", s.Label, j, want, v)
+			}
+		}
+	}
+
+	invalid := []struct {
+		start, end, period uint
+	}{
+		{1600000180, 1600000000, 60},
+		{1600000000, 1600000000, 60},
+		{1600000000, 1600000180, 0},
+	}
+	for _, test := range invalid {
+		if _, err := ctxt.client.ExecuteCAQL("1", test.start, test.end, test.period); err == nil {
+			t.Errorf("%+v: expected an error", test)
+		}
+	}
+	if len(queries) != 1 {
+		t.Errorf("expected the invalid queries not to be sent, got %v", queries[1:])
+	}
+}
+
+func TestAPICAQLSeriesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"histogram data", `{"meta": [{"kind": "histogram"}], "data": [[{"+10e-001": 3}]]}`},
+		{"missing data", `{"meta": [{"kind": "numeric"}, {"kind": "numeric"}], "data": [[1]]}`},
+	}
+	for _, test := range tests {
+		var result api.CAQLResult
+		if err := json.Unmarshal([]byte(test.body), &result); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if _, err := result.Series(); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	// an empty result has no series
+	series, err := (&api.CAQLResult{}).Series()
+	if err != nil || len(series) != 0 {
+		t.Errorf("expected no series, got %v (%v)", series, err)
+	}
+}
+
+func TestAPIExecuteCAQLRejected(t *testing.T) {
+	ctxt, srv := testAPIProviderContext(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code": "ParseError", "message": "unknown function"}`))
+	})
+	defer srv.Close()
+
+	_, err := ctxt.client.ExecuteCAQL("nope()", 1600000000, 1600000180, 60)
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a 400 APIError, got %v", err)
+	}
+}
+
+func TestAPICheckBundleMetricLimit(t *testing.T) {
+	disabled := 0
+	tests := []struct {
+		limit    *int
+		expected string
+	}{
+		{nil, ""},
+		{&disabled, `"metric_limit":0`},
+		{api.NewCheckBundle().MetricLimit, `"metric_limit":-1`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(&api.CheckBundle{MetricLimit: test.limit})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		switch {
+		case test.expected == "" && strings.Contains(string(b), `"metric_limit"`):
+			t.Errorf("expected an unset metric_limit not to be sent, got %s", b)
+		case test.expected != "" && !strings.Contains(string(b), test.expected):
+			t.Errorf("expected %s to be sent, got %s", test.expected, b)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package circonus
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -12,6 +14,10 @@ import (
 
 type circonusCheck struct {
 	api.CheckBundle
+
+	// externalMetrics is set when the metrics of the check bundle are managed
+	// by a circonus_check_metrics resource, updates keep the bundle's metrics.
+	externalMetrics bool
 }
 
 type circonusCheckType string
@@ -33,6 +39,7 @@ const (
 	apiCheckTypeMemcached  circonusCheckType = "memcached"
 	apiCheckTypeJSON       circonusCheckType = "json"
 	apiCheckTypeMySQL      circonusCheckType = "mysql"
+	apiCheckTypeNAD        circonusCheckType = "json,nad"
 	apiCheckTypeNTP        circonusCheckType = "ntp"
 	apiCheckTypeRedis      circonusCheckType = "redis"
 	apiCheckTypeSMTP       circonusCheckType = "smtp"
@@ -43,6 +50,17 @@ const (
 	apiCheckTypeTCP        circonusCheckType = "tcp"
 )
 
+// checkCertMetricNames are the metrics describing the certificate of the
+// target, e.g. cert_end_in is the number of seconds until it expires.
+var checkCertMetricNames = []string{
+	"cert_end",
+	"cert_end_in",
+	"cert_error",
+	"cert_issuer",
+	"cert_start",
+	"cert_subject",
+}
+
 func newCheck() circonusCheck {
 	return circonusCheck{
 		CheckBundle: *api.NewCheckBundle(),
@@ -83,6 +101,14 @@ func checkActiveToAPIStatus(active bool) string {
 }
 
 func (c *circonusCheck) Create(ctxt *providerContext) error {
+	if err := c.ValidateCollectors(ctxt); err != nil {
+		return err
+	}
+
+	if err := c.ValidateComposite(ctxt); err != nil {
+		return err
+	}
+
 	cb, err := ctxt.client.CreateCheckBundle(&c.CheckBundle)
 	if err != nil {
 		return err
@@ -94,6 +120,23 @@ func (c *circonusCheck) Create(ctxt *providerContext) error {
 }
 
 func (c *circonusCheck) Update(ctxt *providerContext) error {
+	if err := c.ValidateCollectors(ctxt); err != nil {
+		return err
+	}
+
+	if err := c.ValidateComposite(ctxt); err != nil {
+		return err
+	}
+
+	if c.externalMetrics {
+		cid := c.CID
+		cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&cid))
+		if err != nil {
+			return fmt.Errorf("Unable to fetch the metrics of check bundle %s: %w", c.CID, err)
+		}
+		c.Metrics = cb.Metrics
+	}
+
 	_, err := ctxt.client.UpdateCheckBundle(&c.CheckBundle)
 	if err != nil {
 		return fmt.Errorf("Unable to update check bundle %s: %w", c.CID, err)
@@ -102,6 +145,35 @@ func (c *circonusCheck) Update(ctxt *providerContext) error {
 	return nil
 }
 
+// ValidateCollectors verifies that every collector of the check supports the
+// module of the check's type (e.g. json for a json,nad check).  Collectors not
+// reporting any modules are not checked.
+func (c *circonusCheck) ValidateCollectors(ctxt *providerContext) error {
+	module := strings.SplitN(c.Type, ",", 2)[0]
+
+	for _, cid := range c.Brokers {
+		brokerCID := cid
+		broker, err := ctxt.client.FetchBroker(api.CIDType(&brokerCID))
+		if err != nil {
+			return fmt.Errorf("unable to fetch %s %q: %w", checkCollectorAttr, cid, err)
+		}
+
+		reportsModules := false
+		for _, detail := range broker.Details {
+			if len(detail.Modules) > 0 {
+				reportsModules = true
+				break
+			}
+		}
+
+		if reportsModules && !brokerSupportsModules(broker, []string{module}) {
+			return fmt.Errorf("%s %q (%s) does not support %s checks", checkCollectorAttr, cid, broker.Name, c.Type)
+		}
+	}
+
+	return nil
+}
+
 func (c *circonusCheck) Fixup() error {
 	switch apiCheckType(c.Type) {
 	case apiCheckTypeCloudWatchAttr:
@@ -118,11 +190,12 @@ func (c *circonusCheck) Fixup() error {
 
 func (c *circonusCheck) Validate() error {
 	// there must be at least 1 metric or at least 1 metric_filter but only one of the lists can contain members.
+	// A check with external metrics starts without metrics, circonus_check_metrics adds them.
 	if len(c.Metrics) > 0 && len(c.MetricFilters) > 0 {
 		return fmt.Errorf("Metrics and MetricFilters both have entries, you can only have one or the other")
 	}
 
-	if len(c.Metrics) == 0 && len(c.MetricFilters) == 0 {
+	if len(c.Metrics) == 0 && len(c.MetricFilters) == 0 && !c.externalMetrics {
 		return fmt.Errorf("You must supply one or more 'metric' blocks *or* one or more 'metric_filter' blocks")
 	}
 
@@ -136,11 +209,49 @@ func (c *circonusCheck) Validate() error {
 		if !(c.Period == 60 || c.Period == 300) {
 			return fmt.Errorf("Period must be either 1m or 5m for a %s check", apiCheckTypeCloudWatchAttr)
 		}
+	case apiCheckTypeCompositeAttr:
+		metricName := c.Config[config.CompositeMetricName]
+		found := false
+		for _, m := range c.Metrics {
+			if m.Name == metricName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s check must have a metric named %q, the %s", checkCompositeAttr, metricName, checkCompositeMetricNameAttr)
+		}
 	case apiCheckTypeConsulAttr:
 		if v, found := c.Config[config.URL]; !found || v == "" {
 			return fmt.Errorf("%s must have at least one check mode set: %s, %s, or %s must be set", checkConsulAttr, checkConsulServiceAttr, checkConsulNodeAttr, checkConsulStateAttr)
 		}
 	}
 
+	// the fields and config settings required by the API, checked before the
+	// check bundle is sent
+	if err := api.ValidateCheckBundle(&c.CheckBundle); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// CertMetricNames returns the names of the certificate metrics of the check:
+// http checks of an https URL and tcp checks using TLS collect them.  It
+// returns nil for other checks.
+func (c *circonusCheck) CertMetricNames() []string {
+	switch apiCheckType(c.Type) {
+	case apiCheckTypeHTTPAttr:
+		if !strings.HasPrefix(strings.ToLower(c.Config[config.URL]), "https://") {
+			return nil
+		}
+	case apiCheckTypeTCPAttr:
+		if tls, _ := strconv.ParseBool(c.Config[config.UseSSL]); !tls {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return append([]string(nil), checkCertMetricNames...)
+}
@@ -13,14 +13,21 @@ const (
 	// When hashing a Set, default to a buffer this size
 	defaultHashBufSize = 512
 
-	providerAPIURLAttr  = "api_url"
-	providerAutoTagAttr = "auto_tag"
-	providerKeyAttr     = "key"
+	providerAPICAFileAttr    = "api_ca_file"
+	providerAPIProxyURLAttr  = "api_proxy_url"
+	providerAPITokenEnvAttr  = "api_token_env"
+	providerAPITokenFileAttr = "api_token_file"
+	providerAPIURLAttr       = "api_url"
+	providerAutoTagAttr      = "auto_tag"
+	providerDefaultTagsAttr  = "default_tags"
+	providerKeyAttr          = "key"
+	providerTLSInsecureAttr  = "tls_insecure"
 
 	apiConsulCheckBlacklist    = "check_name_blacklist"
 	apiConsulDatacenterAttr    = "dc"
 	apiConsulNodeBlacklist     = "node_blacklist"
 	apiConsulServiceBlacklist  = "service_blacklist"
+	apiConsulServiceWhitelist  = "service_whitelist"
 	apiConsulStaleAttr         = "stale"
 	checkConsulTokenHeader     = `X-Consul-Token`
 	checkConsulV1NodePrefix    = "node"
@@ -37,9 +44,16 @@ const (
 	defaultCheckICMPPingAvailability = 100.0
 	defaultCheckICMPPingCount        = 5
 	defaultCheckICMPPingInterval     = "2s"
+	defaultCheckICMPPingPacketSize   = 56
+
+	// maxCheckICMPPingPacketSize is the largest ICMP payload fitting into a
+	// 1500 byte IP MTU: 1500 - 20 (IPv4 header) - 8 (ICMP header)
+	maxCheckICMPPingPacketSize = 1472
 
 	defaultCheckCAQLTarget = "q._caql"
 
+	defaultCheckCompositeTarget = "q._composite"
+
 	defaultCheckHTTPCodeRegexp = `^200$`
 	defaultCheckHTTPMethod     = "GET"
 	defaultCheckHTTPVersion    = "1.1"
@@ -47,6 +61,8 @@ const (
 
 	defaultCheckHTTPTrapAsync = false
 
+	defaultCheckMemcachedPort = 11211
+
 	defaultCheckCloudWatchVersion = "2010-08-01"
 
 	defaultCollectorDetailAttrs = 10
@@ -57,14 +73,15 @@ const (
 
 	defaultDashboardWidgets = 1
 
-	// defaultRuleSetLast       = "300s"
+	defaultRuleSetAtLeast    = "0"
+	defaultRuleSetLast       = "300"
 	defaultRuleSetMetricType = "numeric"
 	defaultRuleSetRuleLen    = 4
 	defaultAlertSeverity     = 1
-	// defaultRuleSetWindowFunc = "average"
+	defaultRuleSetWindowFunc = "average"
 	// ruleSetAbsentMin         = "70s"
 
-	defaultWorkspaceFavourite = false
+	defaultWorkspaceFavorite = false
 )
 
 // Consts and their close relative, Go pseudo-consts.
@@ -79,6 +96,32 @@ var validMetricTypes = validStringValues{
 	`text`,
 }
 
+// validCheckTypes: See `type`: https://login.circonus.com/resources/api/calls/check_bundle
+var validCheckTypes = validStringValues{
+	validString(apiCheckTypeCAQLAttr),
+	validString(apiCheckTypeCloudWatchAttr),
+	validString(apiCheckTypeCompositeAttr),
+	validString(apiCheckTypeConsulAttr),
+	validString(apiCheckTypeDNSAttr),
+	validString(apiCheckTypeExternalAttr),
+	validString(apiCheckTypeHTTPAttr),
+	validString(apiCheckTypeHTTPTrapAttr),
+	validString(apiCheckTypeJMXAttr),
+	validString(apiCheckTypeMemcachedAttr),
+	validString(apiCheckTypeICMPPingAttr),
+	validString(apiCheckTypeJSONAttr),
+	validString(apiCheckTypeMySQLAttr),
+	validString(apiCheckTypeNADAttr),
+	validString(apiCheckTypeNTPAttr),
+	validString(apiCheckTypePostgreSQLAttr),
+	validString(apiCheckTypePromTextAttr),
+	validString(apiCheckTypeRedisAttr),
+	validString(apiCheckTypeSMTPAttr),
+	validString(apiCheckTypeSNMPAttr),
+	validString(apiCheckTypeStatsdAttr),
+	validString(apiCheckTypeTCPAttr),
+}
+
 // validAggregateFuncs: See `aggregate_function`: https://login.circonus.com/resources/api/calls/graph
 var validAggregateFuncs = validStringValues{
 	`none`,
@@ -96,9 +139,11 @@ var validGraphLineStyles = validStringValues{
 }
 
 // validGraphStyles: See `style`: https://login.circonus.com/resources/api/calls/graph
+// `stacked` is a provider preset rendered as `area`.
 var validGraphStyles = validStringValues{
 	`area`,
 	`line`,
+	`stacked`,
 }
 
 // validAxisAttrs: See `line_style`: https://login.circonus.com/resources/api/calls/graph
@@ -107,6 +152,47 @@ var validAxisAttrs = validStringValues{
 	`right`,
 }
 
+// validBrokerTypes: See `_type`: https://login.circonus.com/resources/api/calls/broker
+var validBrokerTypes = validStringValues{
+	`circonus`,
+	`enterprise`,
+}
+
+// validMaintenanceTypes: See `type`: https://login.circonus.com/resources/api/calls/maintenance
+var validMaintenanceTypes = validStringValues{
+	"account",
+	"check",
+	"host",
+	"rule_set",
+	"set",
+}
+
+// validMaintenanceSeverities: See `severities`: https://login.circonus.com/resources/api/calls/maintenance
+var validMaintenanceSeverities = validStringValues{
+	"1",
+	"2",
+	"3",
+	"4",
+	"5",
+}
+
+// validCheckDNSRTypes: See `rtype`: https://login.circonus.com/resources/api/calls/check_bundle
+var validCheckDNSRTypes = validStringValues{
+	"A",
+	"AAAA",
+	"TXT",
+	"MX",
+	"SOA",
+	"CNAME",
+	"PTR",
+	"NS",
+	"MB",
+	"MD",
+	"MF",
+	"MG",
+	"MR",
+}
+
 // validGraphFunctionValues: See `derive`: https://login.circonus.com/resources/api/calls/graph
 var validGraphFunctionValues = validStringValues{
 	`counter`,
@@ -114,6 +200,26 @@ var validGraphFunctionValues = validStringValues{
 	`gauge`,
 }
 
+// validMetricClusterTypes: See `queries[].type`: https://login.circonus.com/resources/api/calls/metric_cluster
+var validMetricClusterTypes = validStringValues{
+	`average`,
+	`count`,
+	`counter`,
+	`counter_stddev`,
+	`derive`,
+	`derive_stddev`,
+	`histogram`,
+	`stddev`,
+	`text`,
+}
+
+// validMetricClusterPatternTypes are the kinds of metric name patterns
+// supported by circonus_metric_cluster query blocks
+var validMetricClusterPatternTypes = validStringValues{
+	metricClusterPatternTypeGlob,
+	metricClusterPatternTypeRegex,
+}
+
 // validRuleSetWindowFuncs: See `derive` or `windowing_func`: https://login.circonus.com/resources/api/calls/rule_set
 var validRuleSetWindowFuncs = validStringValues{
 	`average`,
@@ -131,12 +237,14 @@ var validRuleSetWindowFuncs = validStringValues{
 const (
 	// Supported circonus_trigger.metric_types.  See `metric_type`:
 	// https://login.circonus.com/resources/api/calls/rule_set
-	ruleSetMetricTypeNumeric = "numeric"
-	ruleSetMetricTypeText    = "text"
+	ruleSetMetricTypeHistogram = "histogram"
+	ruleSetMetricTypeNumeric   = "numeric"
+	ruleSetMetricTypeText      = "text"
 )
 
 // validRuleSetMetricTypes: See `metric_type`: https://login.circonus.com/resources/api/calls/rule_set
 var validRuleSetMetricTypes = validStringValues{
+	ruleSetMetricTypeHistogram,
 	ruleSetMetricTypeNumeric,
 	ruleSetMetricTypeText,
 }
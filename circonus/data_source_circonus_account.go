@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -21,6 +22,8 @@ const (
 	accountIDAttr            = "id"
 	accountInvitesAttr       = "invites"
 	accountLimitAttr         = "limit"
+	accountMetricLimitAttr   = "metric_limit"
+	accountMetricsUsedAttr   = "metrics_used"
 	accountNameAttr          = "name"
 	accountOwnerAttr         = "owner"
 	accountRoleAttr          = "role"
@@ -37,6 +40,8 @@ const (
 var accountDescription = map[schemaAttr]string{
 	accountContactGroupsAttr: "Contact Groups in this account",
 	accountInvitesAttr:       "Outstanding invites attached to the account",
+	accountMetricLimitAttr:   "The account's metric limit (0 if the account has no metric limit)",
+	accountMetricsUsedAttr:   "The number of metrics used by the account",
 	accountUsageAttr:         "Account's usage limits",
 	accountUsersAttr:         "Users attached to this account",
 }
@@ -118,6 +123,16 @@ func dataSourceCirconusAccount() *schema.Resource {
 					},
 				},
 			},
+			accountMetricLimitAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: accountDescription[accountMetricLimitAttr],
+			},
+			accountMetricsUsedAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: accountDescription[accountMetricsUsedAttr],
+			},
 			accountNameAttr: {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -220,6 +235,7 @@ func dataSourceCirconusAccountRead(d *schema.ResourceData, meta interface{}) err
 		})
 	}
 
+	var metricLimit, metricsUsed uint
 	usageList := make([]interface{}, 0, len(a.Usage))
 	for i := range a.Usage {
 		usageList = append(usageList, map[string]interface{}{
@@ -227,6 +243,12 @@ func dataSourceCirconusAccountRead(d *schema.ResourceData, meta interface{}) err
 			accountTypeAttr:  a.Usage[i].Type,
 			accountUsedAttr:  a.Usage[i].Used,
 		})
+
+		// the metric usage is reported with a _type of "Metric" or "Metrics"
+		if strings.HasPrefix(strings.ToLower(a.Usage[i].Type), "metric") {
+			metricLimit += a.Usage[i].Limit
+			metricsUsed += a.Usage[i].Used
+		}
 	}
 
 	usersList := make([]interface{}, 0, len(a.Users))
@@ -252,6 +274,8 @@ func dataSourceCirconusAccountRead(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("Unable to store account %q attribute: %w", accountInvitesAttr, err)
 	}
 
+	_ = d.Set(accountMetricLimitAttr, int(metricLimit))
+	_ = d.Set(accountMetricsUsedAttr, int(metricsUsed))
 	_ = d.Set(accountNameAttr, a.Name)
 	_ = d.Set(accountOwnerAttr, a.OwnerCID)
 	_ = d.Set(accountStateProvAttr, a.StateProv)
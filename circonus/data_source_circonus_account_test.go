@@ -17,6 +17,8 @@ func TestAccDataSourceCirconusAccount(t *testing.T) {
 				Config: testAccDataSourceCirconusAccountCurrentConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccDataSourceCirconusAccountCheck("data.circonus_account.by_current", "/account/4536"),
+					resource.TestCheckResourceAttrSet("data.circonus_account.by_current", "metric_limit"),
+					resource.TestCheckResourceAttrSet("data.circonus_account.by_current", "metrics_used"),
 				),
 			},
 		},
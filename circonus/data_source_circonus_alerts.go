@@ -0,0 +1,234 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	alertAlertsAttr        = "alerts"
+	alertCheckAttr         = "check"
+	alertClearedOnAttr     = "cleared_on"
+	alertCountAttr         = "alert_count"
+	alertIDAttr            = "id"
+	alertInMaintenanceAttr = "in_maintenance"
+	alertMetricIDAttr      = "metric_id"
+	alertMetricNameAttr    = "metric_name"
+	alertOccurredOnAttr    = "occurred_on"
+	alertSeverityAttr      = "severity"
+	alertStartAttr         = "start"
+	alertStopAttr          = "stop"
+	alertValueAttr         = "value"
+)
+
+var alertDescription = map[schemaAttr]string{
+	alertAlertsAttr:   "Alerts matching the search criteria, most recent first",
+	alertCheckAttr:    "Only return alerts of this check",
+	alertCountAttr:    "The number of matching alerts",
+	alertSeverityAttr: "Only return alerts of these severities (1-5)",
+	alertStartAttr:    "Only return alerts that occurred at, or after, this time (RFC3339)",
+	alertStopAttr:     "Only return alerts that occurred at, or before, this time (RFC3339)",
+}
+
+func dataSourceCirconusAlerts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusAlertsRead,
+
+		Schema: map[string]*schema.Schema{
+			alertAlertsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: alertDescription[alertAlertsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						alertCheckAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertClearedOnAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertInMaintenanceAttr: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						alertMetricIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertMetricNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertOccurredOnAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						alertSeverityAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						alertValueAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			alertCheckAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(alertCheckAttr, config.CheckCIDRegex),
+				Description:  alertDescription[alertCheckAttr],
+			},
+			alertCountAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: alertDescription[alertCountAttr],
+			},
+			alertSeverityAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: alertDescription[alertSeverityAttr],
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(1, 5),
+				},
+			},
+			alertStartAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  alertDescription[alertStartAttr],
+			},
+			alertStopAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  alertDescription[alertStopAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusAlertsRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var check, startRaw, stopRaw string
+	var start, stop time.Time
+	if v, ok := d.GetOk(alertCheckAttr); ok {
+		check = v.(string)
+	}
+	if v, ok := d.GetOk(alertStartAttr); ok {
+		startRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, startRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", alertStartAttr, startRaw, err)
+		}
+		start = t
+	}
+	if v, ok := d.GetOk(alertStopAttr); ok {
+		stopRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, stopRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", alertStopAttr, stopRaw, err)
+		}
+		stop = t
+	}
+
+	if stopRaw != "" && stop.Before(start) {
+		return fmt.Errorf("%s (%s) must not be before %s (%s)", alertStopAttr, stopRaw, alertStartAttr, startRaw)
+	}
+
+	severities := make(map[uint]bool)
+	var severityList []uint
+	var severityStrs []string
+	if v, ok := d.GetOk(alertSeverityAttr); ok {
+		for _, s := range v.(*schema.Set).List() {
+			severities[uint(s.(int))] = true
+			severityList = append(severityList, uint(s.(int)))
+		}
+		sort.Slice(severityList, func(i, j int) bool { return severityList[i] < severityList[j] })
+		for _, s := range severityList {
+			severityStrs = append(severityStrs, strconv.FormatUint(uint64(s), 10))
+		}
+	}
+
+	filter := api.AlertFilter(start, stop, severityList...)
+	if check != "" {
+		filter["f__check"] = []string{check}
+	}
+
+	alerts, err := ctxt.client.SearchAlerts(nil, &filter)
+	if err != nil {
+		return err
+	}
+
+	// the filters are applied by the API, verify them to be safe
+	matches := make([]api.Alert, 0, len(*alerts))
+	for _, a := range *alerts {
+		if check != "" && a.CheckCID != check {
+			continue
+		}
+		if len(severities) > 0 && !severities[a.Severity] {
+			continue
+		}
+		if startRaw != "" && int64(a.OccurredOn) < start.Unix() {
+			continue
+		}
+		if stopRaw != "" && int64(a.OccurredOn) > stop.Unix() {
+			continue
+		}
+		matches = append(matches, a)
+	}
+
+	// most recent first, the API does not guarantee an order
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].OccurredOn > matches[j].OccurredOn
+	})
+
+	alertList := make([]interface{}, 0, len(matches))
+	for i := range matches {
+		a := &matches[i]
+
+		var clearedOn string
+		if a.Cleared() {
+			clearedOn = time.Unix(int64(*a.ClearedOn), 0).UTC().Format(time.RFC3339)
+		}
+
+		alertList = append(alertList, map[string]interface{}{
+			alertCheckAttr:         a.CheckCID,
+			alertClearedOnAttr:     clearedOn,
+			alertIDAttr:            a.CID,
+			alertInMaintenanceAttr: a.InMaintenance(),
+			alertMetricIDAttr:      a.MetricCID(),
+			alertMetricNameAttr:    a.MetricName,
+			alertOccurredOnAttr:    time.Unix(int64(a.OccurredOn), 0).UTC().Format(time.RFC3339),
+			alertSeverityAttr:      int(a.Severity),
+			alertValueAttr:         a.Value,
+		})
+	}
+
+	d.SetId(hashcode.Strings(append([]string{check, startRaw, stopRaw}, severityStrs...)))
+
+	if err := d.Set(alertAlertsAttr, alertList); err != nil {
+		return fmt.Errorf("Unable to store alert %q attribute: %w", alertAlertsAttr, err)
+	}
+
+	_ = d.Set(alertCountAttr, len(alertList))
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusAlerts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusAlertsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.circonus_alerts.critical", "alert_count"),
+					resource.TestCheckResourceAttrSet("data.circonus_alerts.critical", "alerts.#"),
+				),
+			},
+			{
+				Config:      testAccDataSourceCirconusAlertsInvertedConfig,
+				ExpectError: regexp.MustCompile(`must not be before start`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusAlertsConfig = `
+data "circonus_alerts" "critical" {
+  severity = [1]
+  start = "2020-01-01T00:00:00Z"
+}
+`
+
+const testAccDataSourceCirconusAlertsInvertedConfig = `
+data "circonus_alerts" "inverted" {
+  start = "2020-01-02T00:00:00Z"
+  stop = "2020-01-01T00:00:00Z"
+}
+`
@@ -0,0 +1,195 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	annotationAnnotationsAttr    = "annotations"
+	annotationCategoryAttr       = "category"
+	annotationDescriptionAttr    = "description"
+	annotationIDAttr             = "id"
+	annotationMostRecentAttr     = "most_recent"
+	annotationRelatedMetricsAttr = "related_metrics"
+	annotationStartAttr          = "start"
+	annotationStopAttr           = "stop"
+	annotationTitleAttr          = "title"
+)
+
+var annotationDescription = map[schemaAttr]string{
+	annotationAnnotationsAttr: "Annotations matching the search criteria, most recent first",
+	annotationCategoryAttr:    "The category of the annotations",
+	annotationMostRecentAttr:  "Only return the most recent matching annotation",
+	annotationStartAttr:       "Only return annotations that end at, or after, this time (RFC3339)",
+	annotationStopAttr:        "Only return annotations that start at, or before, this time (RFC3339)",
+}
+
+func dataSourceCirconusAnnotation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusAnnotationRead,
+
+		Schema: map[string]*schema.Schema{
+			annotationAnnotationsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: annotationDescription[annotationAnnotationsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						annotationCategoryAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						annotationDescriptionAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						annotationIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						annotationRelatedMetricsAttr: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						annotationStartAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						annotationStopAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						annotationTitleAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			annotationCategoryAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(annotationCategoryAttr, `.+`),
+				Description:  annotationDescription[annotationCategoryAttr],
+			},
+			annotationMostRecentAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: annotationDescription[annotationMostRecentAttr],
+			},
+			annotationStartAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  annotationDescription[annotationStartAttr],
+			},
+			annotationStopAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  annotationDescription[annotationStopAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusAnnotationRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var category, startRaw, stopRaw string
+	var start, stop uint
+	if v, ok := d.GetOk(annotationCategoryAttr); ok {
+		category = v.(string)
+	}
+	if v, ok := d.GetOk(annotationStartAttr); ok {
+		startRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, startRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", annotationStartAttr, startRaw, err)
+		}
+		start = uint(t.Unix())
+	}
+	if v, ok := d.GetOk(annotationStopAttr); ok {
+		stopRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, stopRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", annotationStopAttr, stopRaw, err)
+		}
+		stop = uint(t.Unix())
+	}
+
+	if stopRaw != "" && stop < start {
+		return fmt.Errorf("%s (%s) must not be before %s (%s)", annotationStopAttr, stopRaw, annotationStartAttr, startRaw)
+	}
+
+	var filter *api.SearchFilterType
+	if category != "" {
+		filter = &api.SearchFilterType{"f_category": []string{category}}
+	}
+
+	annotations, err := ctxt.client.SearchAnnotationsUnique(nil, filter)
+	if err != nil {
+		return err
+	}
+
+	matches := make([]api.Annotation, 0, len(*annotations))
+	for _, a := range *annotations {
+		if category != "" && a.Category != category {
+			continue
+		}
+		if startRaw != "" && a.Stop < start {
+			continue
+		}
+		if stopRaw != "" && a.Start > stop {
+			continue
+		}
+		matches = append(matches, a)
+	}
+
+	// most recent first, the API does not guarantee an order
+	sortAnnotationsMostRecentFirst(matches)
+
+	if d.Get(annotationMostRecentAttr).(bool) && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	annotationList := make([]interface{}, 0, len(matches))
+	for _, a := range matches {
+		annotationList = append(annotationList, map[string]interface{}{
+			annotationCategoryAttr:       a.Category,
+			annotationDescriptionAttr:    a.Description,
+			annotationIDAttr:             a.CID,
+			annotationRelatedMetricsAttr: a.RelatedMetrics,
+			annotationStartAttr:          a.StartTime().UTC().Format(time.RFC3339),
+			annotationStopAttr:           a.StopTime().UTC().Format(time.RFC3339),
+			annotationTitleAttr:          a.Title,
+		})
+	}
+
+	d.SetId(hashcode.Strings([]string{category, startRaw, stopRaw, fmt.Sprintf("%t", d.Get(annotationMostRecentAttr).(bool))}))
+
+	if err := d.Set(annotationAnnotationsAttr, annotationList); err != nil {
+		return fmt.Errorf("Unable to store annotation %q attribute: %w", annotationAnnotationsAttr, err)
+	}
+
+	return nil
+}
+
+// sortAnnotationsMostRecentFirst sorts annotations by start time, most recent
+// first.  Annotations with the same start time are kept in their original order.
+func sortAnnotationsMostRecentFirst(annotations []api.Annotation) {
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].Start > annotations[j].Start
+	})
+}
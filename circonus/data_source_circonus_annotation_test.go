@@ -0,0 +1,42 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusAnnotation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusAnnotationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.circonus_annotation.recent", "annotations.#"),
+				),
+			},
+			{
+				Config:      testAccDataSourceCirconusAnnotationInvertedConfig,
+				ExpectError: regexp.MustCompile(`must not be before start`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusAnnotationConfig = `
+data "circonus_annotation" "recent" {
+  category = "deploy"
+  start = "2020-01-01T00:00:00Z"
+  most_recent = true
+}
+`
+
+const testAccDataSourceCirconusAnnotationInvertedConfig = `
+data "circonus_annotation" "inverted" {
+  start = "2020-01-02T00:00:00Z"
+  stop = "2020-01-01T00:00:00Z"
+}
+`
@@ -0,0 +1,194 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	brokerDetailsAttr   = "details"
+	brokerIDAttr        = "id"
+	brokerLatitudeAttr  = "latitude"
+	brokerLongitudeAttr = "longitude"
+	brokerNameAttr      = "name"
+	brokerSupportsAttr  = "supports"
+	brokerTagsAttr      = "tags"
+	brokerTypeAttr      = "type"
+)
+
+var brokerDescription = map[schemaAttr]string{
+	brokerIDAttr:       "The Circonus ID of the broker",
+	brokerNameAttr:     "The name of the broker, used to disambiguate when multiple brokers match",
+	brokerSupportsAttr: "A list of module names (e.g. cloudwatch) the broker must support",
+	brokerTagsAttr:     "Tags assigned to the broker",
+	brokerTypeAttr:     "The type of broker, either enterprise or circonus",
+}
+
+func dataSourceCirconusBroker() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusBrokerRead,
+
+		Schema: map[string]*schema.Schema{
+			brokerDetailsAttr: collectorDetailsSchema(),
+			brokerIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateRegexp(brokerIDAttr, config.BrokerCIDRegex),
+				Description:  brokerDescription[brokerIDAttr],
+			},
+			brokerLatitudeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: brokerDescription[brokerLatitudeAttr],
+			},
+			brokerLongitudeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: brokerDescription[brokerLongitudeAttr],
+			},
+			brokerNameAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: brokerDescription[brokerNameAttr],
+			},
+			brokerSupportsAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRegexp(brokerSupportsAttr, `.+`),
+				},
+				Description: brokerDescription[brokerSupportsAttr],
+			},
+			brokerTagsAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: brokerDescription[brokerTagsAttr],
+			},
+			brokerTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringIn(brokerTypeAttr, validBrokerTypes),
+				Description:  brokerDescription[brokerTypeAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusBrokerRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var brokerType, brokerName string
+	if v, ok := d.GetOk(brokerTypeAttr); ok {
+		brokerType = v.(string)
+	}
+	if v, ok := d.GetOk(brokerNameAttr); ok {
+		brokerName = v.(string)
+	}
+
+	var supports []string
+	if v, ok := d.GetOk(brokerSupportsAttr); ok {
+		supports = derefStringList(flattenList(v.([]interface{})))
+	}
+
+	var candidates []api.Broker
+	if cidRaw, ok := d.GetOk(brokerIDAttr); ok {
+		cid := cidRaw.(string)
+		broker, err := ctxt.client.FetchBroker(api.CIDType(&cid))
+		if err != nil {
+			return err
+		}
+		candidates = []api.Broker{*broker}
+	} else {
+		brokers, err := ctxt.client.FetchBrokers()
+		if err != nil {
+			return err
+		}
+		candidates = *brokers
+	}
+
+	matches := make([]api.Broker, 0, len(candidates))
+	for _, broker := range candidates {
+		if brokerType != "" && broker.Type != brokerType {
+			continue
+		}
+		if brokerName != "" && broker.Name != brokerName {
+			continue
+		}
+		if !brokerSupportsModules(&broker, supports) {
+			continue
+		}
+		matches = append(matches, broker)
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no broker matched the search criteria")
+	case 1:
+		// noop
+	default:
+		cids := make([]string, 0, len(matches))
+		for _, broker := range matches {
+			cids = append(cids, broker.CID)
+		}
+		sort.Strings(cids)
+		return fmt.Errorf("%d brokers matched the search criteria (%s), use %s or %s to select one", len(matches), strings.Join(cids, ", "), brokerIDAttr, brokerNameAttr)
+	}
+
+	broker := matches[0]
+
+	d.SetId(broker.CID)
+
+	if err := d.Set(brokerDetailsAttr, collectorDetailsToState(&broker)); err != nil {
+		return fmt.Errorf("Unable to store broker %q attribute: %w", brokerDetailsAttr, err)
+	}
+
+	_ = d.Set(brokerIDAttr, broker.CID)
+	_ = d.Set(brokerLatitudeAttr, indirect(broker.Latitude))
+	_ = d.Set(brokerLongitudeAttr, indirect(broker.Longitude))
+	_ = d.Set(brokerNameAttr, broker.Name)
+	_ = d.Set(brokerTagsAttr, broker.Tags)
+	_ = d.Set(brokerTypeAttr, broker.Type)
+
+	return nil
+}
+
+// brokerSupportsModules returns true when at least one of the individual
+// brokers in the broker group supports every module in modules.
+func brokerSupportsModules(b *api.Broker, modules []string) bool {
+	if len(modules) == 0 {
+		return true
+	}
+
+	for _, detail := range b.Details {
+		supported := make(map[string]bool, len(detail.Modules))
+		for _, module := range detail.Modules {
+			supported[module] = true
+		}
+
+		all := true
+		for _, module := range modules {
+			if !supported[module] {
+				all = false
+				break
+			}
+		}
+
+		if all {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceCirconusBroker(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusBrokerConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceCirconusBrokerCheck("data.circonus_broker.by_id", "/broker/1"),
+					resource.TestCheckResourceAttr("data.circonus_broker.by_id", "type", "circonus"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceCirconusBrokerCheck(name, cid string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no resource called %s", name)
+		}
+
+		attr := rs.Primary.Attributes
+
+		if attr[brokerIDAttr] != cid {
+			return fmt.Errorf("bad id %s", attr[brokerIDAttr])
+		}
+
+		return nil
+	}
+}
+
+const testAccDataSourceCirconusBrokerConfig = `
+data "circonus_broker" "by_id" {
+  id = "/broker/1"
+  type = "circonus"
+  supports = [ "http" ]
+}
+`
@@ -0,0 +1,137 @@
+package circonus
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	caqlMetricsAttr = "metrics"
+	caqlPeriodAttr  = "period"
+	caqlQueryAttr   = "query"
+	caqlStartAttr   = "start"
+	caqlStopAttr    = "stop"
+	caqlValidAttr   = "valid"
+)
+
+var caqlDescription = map[schemaAttr]string{
+	caqlMetricsAttr: "The metric references the query resolved to, one per output stream",
+	caqlPeriodAttr:  "The period to evaluate the query at (e.g. 60s)",
+	caqlQueryAttr:   "The CAQL query",
+	caqlStartAttr:   "The start of the time range to evaluate the query over (RFC3339)",
+	caqlStopAttr:    "The end of the time range to evaluate the query over (RFC3339)",
+	caqlValidAttr:   "Whether the query is valid, queries that fail to parse fail the data source",
+}
+
+func dataSourceCirconusCAQL() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusCAQLRead,
+
+		Schema: map[string]*schema.Schema{
+			caqlMetricsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: caqlDescription[caqlMetricsAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			caqlPeriodAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateDurationMin(caqlPeriodAttr, "1s"),
+				),
+				Description: caqlDescription[caqlPeriodAttr],
+			},
+			caqlQueryAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(caqlQueryAttr, `\S`),
+				Description:  caqlDescription[caqlQueryAttr],
+			},
+			caqlStartAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  caqlDescription[caqlStartAttr],
+			},
+			caqlStopAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  caqlDescription[caqlStopAttr],
+			},
+			caqlValidAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: caqlDescription[caqlValidAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusCAQLRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	q := &api.CAQLQuery{
+		Query: d.Get(caqlQueryAttr).(string),
+	}
+
+	var startRaw, stopRaw, periodRaw string
+	if v, ok := d.GetOk(caqlStartAttr); ok {
+		startRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, startRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", caqlStartAttr, startRaw, err)
+		}
+		q.Start = uint(t.Unix())
+	}
+	if v, ok := d.GetOk(caqlStopAttr); ok {
+		stopRaw = v.(string)
+		t, err := time.Parse(time.RFC3339, stopRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", caqlStopAttr, stopRaw, err)
+		}
+		q.End = uint(t.Unix())
+	}
+	if v, ok := d.GetOk(caqlPeriodAttr); ok {
+		periodRaw = v.(string)
+		p, err := time.ParseDuration(periodRaw)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s %q: %w", caqlPeriodAttr, periodRaw, err)
+		}
+		q.Period = uint(p.Seconds())
+	}
+
+	if stopRaw != "" && q.End < q.Start {
+		return fmt.Errorf("%s (%s) must not be before %s (%s)", caqlStopAttr, stopRaw, caqlStartAttr, startRaw)
+	}
+
+	result, err := ctxt.client.QueryCAQL(q)
+	if err != nil {
+		return fmt.Errorf("invalid CAQL %s %q: %w", caqlQueryAttr, q.Query, err)
+	}
+
+	metrics := make([]string, 0, len(result.Meta))
+	for _, m := range result.Meta {
+		metrics = append(metrics, m.Label)
+	}
+
+	d.SetId(hashcode.Strings([]string{q.Query, startRaw, stopRaw, periodRaw}))
+
+	if err := d.Set(caqlMetricsAttr, metrics); err != nil {
+		return fmt.Errorf("Unable to store CAQL %q attribute: %w", caqlMetricsAttr, err)
+	}
+
+	if err := d.Set(caqlValidAttr, true); err != nil {
+		return fmt.Errorf("Unable to store CAQL %q attribute: %w", caqlValidAttr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusCAQL(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusCAQLConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.circonus_caql.cpu", "valid", "true"),
+					resource.TestCheckResourceAttrSet("data.circonus_caql.cpu", "metrics.#"),
+				),
+			},
+			{
+				Config:      testAccDataSourceCirconusCAQLInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid CAQL query`),
+			},
+			{
+				Config:      testAccDataSourceCirconusCAQLInvertedConfig,
+				ExpectError: regexp.MustCompile(`must not be before start`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusCAQLConfig = `
+data "circonus_caql" "cpu" {
+  query = "find('cpu` + "`" + `user') | top(5)"
+  start = "2020-01-01T00:00:00Z"
+  stop = "2020-01-01T01:00:00Z"
+  period = "60s"
+}
+`
+
+const testAccDataSourceCirconusCAQLInvalidConfig = `
+data "circonus_caql" "invalid" {
+  query = "find('cpu"
+}
+`
+
+const testAccDataSourceCirconusCAQLInvertedConfig = `
+data "circonus_caql" "inverted" {
+  query = "find('cpu` + "`" + `user')"
+  start = "2020-01-02T00:00:00Z"
+  stop = "2020-01-01T00:00:00Z"
+}
+`
@@ -0,0 +1,170 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataCheckChecksAttr      = "checks"
+	dataCheckIDAttr          = "id"
+	dataCheckMetricNamesAttr = "metric_names"
+	dataCheckMostRecentAttr  = "most_recent"
+	dataCheckNameAttr        = "name"
+	dataCheckTagsAttr        = "tags"
+	dataCheckTargetAttr      = "target"
+	dataCheckTypeAttr        = "type"
+)
+
+var dataCheckDescription = map[schemaAttr]string{
+	dataCheckChecksAttr:      "The CIDs of the checks of the check bundle, one per broker",
+	dataCheckIDAttr:          "The CID of the check bundle",
+	dataCheckMetricNamesAttr: "The names of the metrics collected by the check, sorted",
+	dataCheckMostRecentAttr:  "Return the most recently created check when several checks match, instead of failing",
+	dataCheckNameAttr:        "The display name of the check",
+	dataCheckTagsAttr:        "Tags assigned to the check",
+	dataCheckTargetAttr:      "The target (host) of the check",
+	dataCheckTypeAttr:        "The type of the check, e.g. http or ping_icmp",
+}
+
+func dataSourceCirconusCheck() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusCheckRead,
+
+		Schema: map[string]*schema.Schema{
+			dataCheckChecksAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: dataCheckDescription[dataCheckChecksAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataCheckIDAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataCheckDescription[dataCheckIDAttr],
+			},
+			dataCheckMetricNamesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: dataCheckDescription[dataCheckMetricNamesAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataCheckMostRecentAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: dataCheckDescription[dataCheckMostRecentAttr],
+			},
+			dataCheckNameAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataCheckDescription[dataCheckNameAttr],
+			},
+			dataCheckTagsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: dataCheckDescription[dataCheckTagsAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataCheckTargetAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(dataCheckTargetAttr, `.+`),
+				Description:  dataCheckDescription[dataCheckTargetAttr],
+			},
+			dataCheckTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringIn(dataCheckTypeAttr, validCheckTypes),
+				Description:  dataCheckDescription[dataCheckTypeAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusCheckRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	target := d.Get(dataCheckTargetAttr).(string)
+	checkType := d.Get(dataCheckTypeAttr).(string)
+
+	// the filters narrow the search, the exact match is verified below
+	filter := api.SearchFilterType{
+		"f_target": []string{target},
+		"f_type":   []string{checkType},
+	}
+	bundles, err := ctxt.client.SearchCheckBundles(nil, &filter)
+	if err != nil {
+		return err
+	}
+
+	cb, err := dataCheckSelect(*bundles, target, checkType, d.Get(dataCheckMostRecentAttr).(bool))
+	if err != nil {
+		return err
+	}
+
+	metricNames := make([]string, 0, len(cb.Metrics))
+	for _, m := range cb.Metrics {
+		if m.Status == checkStatusActive {
+			metricNames = append(metricNames, m.Name)
+		}
+	}
+	sort.Strings(metricNames)
+
+	d.SetId(cb.CID)
+
+	_ = d.Set(dataCheckIDAttr, cb.CID)
+	_ = d.Set(dataCheckNameAttr, cb.DisplayName)
+
+	if err := d.Set(dataCheckChecksAttr, cb.Checks); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", dataCheckChecksAttr, err)
+	}
+
+	if err := d.Set(dataCheckMetricNamesAttr, metricNames); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", dataCheckMetricNamesAttr, err)
+	}
+
+	if err := d.Set(dataCheckTagsAttr, cb.Tags); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", dataCheckTagsAttr, err)
+	}
+
+	return nil
+}
+
+// dataCheckSelect returns the check bundle with the given target and type.
+// Several matches are an error unless mostRecent is set, the most recently
+// created match is returned then.
+func dataCheckSelect(bundles []api.CheckBundle, target, checkType string, mostRecent bool) (*api.CheckBundle, error) {
+	matches := make([]api.CheckBundle, 0, 1)
+	for _, cb := range bundles {
+		if cb.Target == target && cb.Type == checkType {
+			matches = append(matches, cb)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no check found with %s %q and %s %q", dataCheckTargetAttr, target, dataCheckTypeAttr, checkType)
+	case len(matches) > 1 && !mostRecent:
+		cids := make([]string, 0, len(matches))
+		for _, cb := range matches {
+			cids = append(cids, cb.CID)
+		}
+		return nil, fmt.Errorf("%d checks have %s %q and %s %q (%v), set %s to use the most recent one", len(matches), dataCheckTargetAttr, target, dataCheckTypeAttr, checkType, cids, dataCheckMostRecentAttr)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Created > matches[j].Created
+	})
+
+	return &matches[0], nil
+}
@@ -0,0 +1,141 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	checkMetricsActiveOnlyAttr     = "active_only"
+	checkMetricsCheckAttr          = "check"
+	checkMetricsDynamicMetricsAttr = "dynamic_metrics"
+	checkMetricsMetricNamesAttr    = "metric_names"
+	checkMetricsMetricsAttr        = "metrics"
+	checkMetricsNameAttr           = "name"
+	checkMetricsStatusAttr         = "status"
+	checkMetricsTagsAttr           = "tags"
+	checkMetricsTypeAttr           = "type"
+	checkMetricsUnitsAttr          = "units"
+)
+
+var checkMetricsDescription = map[schemaAttr]string{
+	checkMetricsActiveOnlyAttr:     "Only return the metrics the check collects (status active)",
+	checkMetricsCheckAttr:          "The CID of the check bundle whose metrics are returned",
+	checkMetricsDynamicMetricsAttr: "True when the check uses metric filters, the metrics are then only those seen so far",
+	checkMetricsMetricNamesAttr:    "The names of the metrics, sorted",
+	checkMetricsMetricsAttr:        "The metrics of the check, sorted by name",
+}
+
+func dataSourceCirconusCheckMetrics() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusCheckMetricsRead,
+
+		Schema: map[string]*schema.Schema{
+			checkMetricsActiveOnlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: checkMetricsDescription[checkMetricsActiveOnlyAttr],
+			},
+			checkMetricsCheckAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkMetricsCheckAttr, config.CheckBundleCIDRegex),
+				Description:  checkMetricsDescription[checkMetricsCheckAttr],
+			},
+			checkMetricsDynamicMetricsAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: checkMetricsDescription[checkMetricsDynamicMetricsAttr],
+			},
+			checkMetricsMetricNamesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: checkMetricsDescription[checkMetricsMetricNamesAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			checkMetricsMetricsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: checkMetricsDescription[checkMetricsMetricsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						checkMetricsNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						checkMetricsStatusAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						checkMetricsTagsAttr: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						checkMetricsTypeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						checkMetricsUnitsAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCirconusCheckMetricsRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Get(checkMetricsCheckAttr).(string)
+	activeOnly := d.Get(checkMetricsActiveOnlyAttr).(bool)
+
+	list, err := ctxt.client.FetchCheckBundleMetricList(api.CIDType(&cid), activeOnly)
+	if err != nil {
+		return err
+	}
+
+	metrics := list.Metrics
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+
+	metricList := make([]interface{}, 0, len(metrics))
+	metricNames := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		metricList = append(metricList, map[string]interface{}{
+			checkMetricsNameAttr:   m.Name,
+			checkMetricsStatusAttr: m.Status,
+			checkMetricsTagsAttr:   m.Tags,
+			checkMetricsTypeAttr:   m.Type,
+			checkMetricsUnitsAttr:  indirect(m.Units),
+		})
+		metricNames = append(metricNames, m.Name)
+	}
+
+	d.SetId(list.CheckBundleCID)
+
+	if err := d.Set(checkMetricsMetricsAttr, metricList); err != nil {
+		return fmt.Errorf("Unable to store check metrics %q attribute: %w", checkMetricsMetricsAttr, err)
+	}
+
+	if err := d.Set(checkMetricsMetricNamesAttr, metricNames); err != nil {
+		return fmt.Errorf("Unable to store check metrics %q attribute: %w", checkMetricsMetricNamesAttr, err)
+	}
+
+	_ = d.Set(checkMetricsDynamicMetricsAttr, list.DynamicMetrics)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusCheckMetrics(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceCirconusCheckMetricsConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.circonus_check_metrics.icmp", "id", "circonus_check.icmp", "id"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "dynamic_metrics", "false"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "metric_names.#", "2"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "metric_names.0", "average"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "metric_names.1", "maximum"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "metrics.0.type", "numeric"),
+					resource.TestCheckResourceAttr("data.circonus_check_metrics.icmp", "metrics.0.status", "active"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusCheckMetricsConfigFmt = `
+resource "circonus_check" "icmp" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  metric {
+    name = "average"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+data "circonus_check_metrics" "icmp" {
+  check = circonus_check.icmp.id
+  active_only = true
+}
+`
@@ -0,0 +1,98 @@
+package circonus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusCheck(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+	target := fmt.Sprintf("terraform-%s.example.com", acctest.RandString(8))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceCirconusCheckConfigFmt, checkName, target),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.circonus_check.icmp", "id", "circonus_check.icmp", "id"),
+					resource.TestCheckResourceAttr("data.circonus_check.icmp", "name", checkName),
+					resource.TestCheckResourceAttr("data.circonus_check.icmp", "checks.#", "1"),
+					resource.TestCheckResourceAttr("data.circonus_check.icmp", "metric_names.#", "1"),
+					resource.TestCheckResourceAttr("data.circonus_check.icmp", "metric_names.0", "maximum"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataCheckSelect(t *testing.T) {
+	bundles := []api.CheckBundle{
+		{CID: "/check_bundle/1", Target: "www.example.com", Type: "http", Created: 100},
+		{CID: "/check_bundle/2", Target: "www.example.com", Type: "ping_icmp", Created: 200},
+		{CID: "/check_bundle/3", Target: "www.example.com", Type: "ping_icmp", Created: 300},
+		{CID: "/check_bundle/4", Target: "www.example.com.au", Type: "http", Created: 400},
+	}
+
+	tests := []struct {
+		target     string
+		checkType  string
+		mostRecent bool
+		cid        string
+		err        string
+	}{
+		{"www.example.com", "http", false, "/check_bundle/1", ""},
+		{"www.example.com", "ping_icmp", false, "", "2 checks have target"},
+		{"www.example.com", "ping_icmp", true, "/check_bundle/3", ""},
+		{"www.example.com", "dns", true, "", "no check found"},
+	}
+
+	for _, test := range tests {
+		cb, err := dataCheckSelect(bundles, test.target, test.checkType, test.mostRecent)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s %s: unexpected error %v", test.target, test.checkType, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s %s: expected error containing %q", test.target, test.checkType, test.err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s %s: expected error containing %q, got %v", test.target, test.checkType, test.err, err)
+		case test.err == "" && cb.CID != test.cid:
+			t.Errorf("%s %s: expected %s, got %s", test.target, test.checkType, test.cid, cb.CID)
+		}
+	}
+}
+
+const testAccDataSourceCirconusCheckConfigFmt = `
+resource "circonus_check" "icmp" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "%s"
+}
+
+data "circonus_check" "icmp" {
+  target = circonus_check.icmp.target
+  type   = "ping_icmp"
+}
+`
@@ -38,68 +38,7 @@ func dataSourceCirconusCollector() *schema.Resource {
 		Read: dataSourceCirconusCollectorRead,
 
 		Schema: map[string]*schema.Schema{
-			collectorDetailsAttr: {
-				Type:        schema.TypeList,
-				Computed:    true,
-				Description: collectorDescription[collectorDetailsAttr],
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						collectorCNAttr: {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: collectorDescription[collectorCNAttr],
-						},
-						collectorExternalHostAttr: {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: collectorDescription[collectorExternalHostAttr],
-						},
-						collectorExternalPortAttr: {
-							Type:        schema.TypeInt,
-							Computed:    true,
-							Description: collectorDescription[collectorExternalPortAttr],
-						},
-						collectorIPAttr: {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: collectorDescription[collectorIPAttr],
-						},
-						collectorMinVersionAttr: {
-							Type:        schema.TypeInt,
-							Computed:    true,
-							Description: collectorDescription[collectorMinVersionAttr],
-						},
-						collectorModulesAttr: {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-							Description: collectorDescription[collectorModulesAttr],
-						},
-						collectorPortAttr: {
-							Type:        schema.TypeInt,
-							Computed:    true,
-							Description: collectorDescription[collectorPortAttr],
-						},
-						collectorSkewAttr: {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: collectorDescription[collectorSkewAttr],
-						},
-						collectorStatusAttr: {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: collectorDescription[collectorStatusAttr],
-						},
-						collectorVersionAttr: {
-							Type:        schema.TypeInt,
-							Computed:    true,
-							Description: collectorDescription[collectorVersionAttr],
-						},
-					},
-				},
-			},
+			collectorDetailsAttr: collectorDetailsSchema(),
 			collectorIDAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -132,6 +71,73 @@ func dataSourceCirconusCollector() *schema.Resource {
 	}
 }
 
+// collectorDetailsSchema returns the schema of the details of the individual
+// collectors (a.k.a. brokers) within a broker group.
+func collectorDetailsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: collectorDescription[collectorDetailsAttr],
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				collectorCNAttr: {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: collectorDescription[collectorCNAttr],
+				},
+				collectorExternalHostAttr: {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: collectorDescription[collectorExternalHostAttr],
+				},
+				collectorExternalPortAttr: {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: collectorDescription[collectorExternalPortAttr],
+				},
+				collectorIPAttr: {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: collectorDescription[collectorIPAttr],
+				},
+				collectorMinVersionAttr: {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: collectorDescription[collectorMinVersionAttr],
+				},
+				collectorModulesAttr: {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Description: collectorDescription[collectorModulesAttr],
+				},
+				collectorPortAttr: {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: collectorDescription[collectorPortAttr],
+				},
+				collectorSkewAttr: {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: collectorDescription[collectorSkewAttr],
+				},
+				collectorStatusAttr: {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: collectorDescription[collectorStatusAttr],
+				},
+				collectorVersionAttr: {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: collectorDescription[collectorVersionAttr],
+				},
+			},
+		},
+	}
+}
+
 func dataSourceCirconusCollectorRead(d *schema.ResourceData, meta interface{}) error {
 	ctxt := meta.(*providerContext)
 
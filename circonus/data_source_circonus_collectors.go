@@ -0,0 +1,160 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	collectorsCollectorsAttr = "collectors"
+	collectorsTagAttr        = "tag"
+	collectorsTypeAttr       = "type"
+)
+
+var collectorsDescription = map[schemaAttr]string{
+	collectorsCollectorsAttr: "The collectors (a.k.a. brokers) matching the filters, sorted by ID",
+	collectorsTagAttr:        "Only return collectors with this tag (e.g. region:us-east)",
+	collectorsTypeAttr:       "Only return collectors of this type, either enterprise or circonus",
+}
+
+func dataSourceCirconusCollectors() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusCollectorsRead,
+
+		Schema: map[string]*schema.Schema{
+			collectorsCollectorsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: collectorsDescription[collectorsCollectorsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						collectorIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						collectorLatitudeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						collectorLongitudeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						collectorModulesAttr: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						collectorNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						collectorTagsAttr: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						collectorTypeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			collectorsTagAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTag,
+				Description:  collectorsDescription[collectorsTagAttr],
+			},
+			collectorsTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringIn(collectorsTypeAttr, validBrokerTypes),
+				Description:  collectorsDescription[collectorsTypeAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusCollectorsRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var collectorType, tag string
+	if v, ok := d.GetOk(collectorsTypeAttr); ok {
+		collectorType = v.(string)
+	}
+	if v, ok := d.GetOk(collectorsTagAttr); ok {
+		tag = strings.ToLower(v.(string))
+	}
+
+	brokers, err := ctxt.client.FetchBrokers()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(*brokers, func(i, j int) bool {
+		return (*brokers)[i].CID < (*brokers)[j].CID
+	})
+
+	collectors := make([]interface{}, 0, len(*brokers))
+	for _, broker := range *brokers {
+		if collectorType != "" && broker.Type != collectorType {
+			continue
+		}
+		if tag != "" && !collectorHasTag(broker.Tags, tag) {
+			continue
+		}
+
+		// the modules supported by any of the individual brokers
+		seen := make(map[string]bool)
+		modules := make([]string, 0)
+		for _, detail := range broker.Details {
+			for _, module := range detail.Modules {
+				if !seen[module] {
+					seen[module] = true
+					modules = append(modules, module)
+				}
+			}
+		}
+		sort.Strings(modules)
+
+		collectors = append(collectors, map[string]interface{}{
+			collectorIDAttr:        broker.CID,
+			collectorLatitudeAttr:  indirect(broker.Latitude),
+			collectorLongitudeAttr: indirect(broker.Longitude),
+			collectorModulesAttr:   modules,
+			collectorNameAttr:      broker.Name,
+			collectorTagsAttr:      broker.Tags,
+			collectorTypeAttr:      broker.Type,
+		})
+	}
+
+	d.SetId(hashcode.Strings([]string{collectorType, tag}))
+
+	if err := d.Set(collectorsCollectorsAttr, collectors); err != nil {
+		return fmt.Errorf("Unable to store collectors %q attribute: %w", collectorsCollectorsAttr, err)
+	}
+
+	return nil
+}
+
+// collectorHasTag reports whether the tag is one of the tags of a collector,
+// tags are compared case-insensitively.
+func collectorHasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.ToLower(t) == tag {
+			return true
+		}
+	}
+
+	return false
+}
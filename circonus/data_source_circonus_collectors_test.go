@@ -0,0 +1,42 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusCollectors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusCollectorsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.circonus_collectors.all", "collectors.#"),
+					resource.TestCheckResourceAttr("data.circonus_collectors.public", "collectors.0.type", "circonus"),
+				),
+			},
+			{
+				Config:      testAccDataSourceCirconusCollectorsInvalidTypeConfig,
+				ExpectError: regexp.MustCompile(`Invalid "type" specified`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusCollectorsConfig = `
+data "circonus_collectors" "all" {}
+
+data "circonus_collectors" "public" {
+  type = "circonus"
+}
+`
+
+const testAccDataSourceCirconusCollectorsInvalidTypeConfig = `
+data "circonus_collectors" "invalid" {
+  type = "public"
+}
+`
@@ -0,0 +1,124 @@
+package circonus
+
+import (
+	"fmt"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dataContactGroupExternalCountAttr = "external_count"
+	dataContactGroupIDAttr            = "id"
+	dataContactGroupNameAttr          = "name"
+	dataContactGroupTagsAttr          = "tags"
+	dataContactGroupUserCountAttr     = "user_count"
+)
+
+var dataContactGroupDescription = map[schemaAttr]string{
+	dataContactGroupExternalCountAttr: "The number of external contacts (e.g. email addresses, webhooks) of the contact group",
+	dataContactGroupIDAttr:            "The ID of the contact group",
+	dataContactGroupNameAttr:          "The exact name of the contact group",
+	dataContactGroupTagsAttr:          "Tags assigned to the contact group",
+	dataContactGroupUserCountAttr:     "The number of user contacts of the contact group",
+}
+
+func dataSourceCirconusContactGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusContactGroupRead,
+
+		Schema: map[string]*schema.Schema{
+			dataContactGroupExternalCountAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: dataContactGroupDescription[dataContactGroupExternalCountAttr],
+			},
+			dataContactGroupIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{dataContactGroupIDAttr, dataContactGroupNameAttr},
+				ValidateFunc: validateRegexp(dataContactGroupIDAttr, config.ContactGroupCIDRegex),
+				Description:  dataContactGroupDescription[dataContactGroupIDAttr],
+			},
+			dataContactGroupNameAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{dataContactGroupIDAttr, dataContactGroupNameAttr},
+				ValidateFunc: validateRegexp(dataContactGroupNameAttr, `.+`),
+				Description:  dataContactGroupDescription[dataContactGroupNameAttr],
+			},
+			dataContactGroupTagsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: dataContactGroupDescription[dataContactGroupTagsAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataContactGroupUserCountAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: dataContactGroupDescription[dataContactGroupUserCountAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusContactGroupRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var cg *api.ContactGroup
+	if v, ok := d.GetOk(dataContactGroupIDAttr); ok {
+		cid := v.(string)
+		g, err := ctxt.client.FetchContactGroup(api.CIDType(&cid))
+		if err != nil {
+			return err
+		}
+		cg = g
+	} else {
+		name := d.Get(dataContactGroupNameAttr).(string)
+
+		// the name filter narrows the search, the exact match is verified below
+		filter := api.SearchFilterType{"f_name": []string{name}}
+		groups, err := ctxt.client.SearchContactGroups(nil, &filter)
+		if err != nil {
+			return err
+		}
+
+		matches := make([]api.ContactGroup, 0, 1)
+		for _, g := range *groups {
+			if g.Name == name {
+				matches = append(matches, g)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return fmt.Errorf("no contact group found with %s %q", dataContactGroupNameAttr, name)
+		case 1:
+			cg = &matches[0]
+		default:
+			cids := make([]string, 0, len(matches))
+			for _, g := range matches {
+				cids = append(cids, g.CID)
+			}
+			return fmt.Errorf("%d contact groups have %s %q (%v), use %s instead", len(matches), dataContactGroupNameAttr, name, cids, dataContactGroupIDAttr)
+		}
+	}
+
+	d.SetId(cg.CID)
+
+	_ = d.Set(dataContactGroupExternalCountAttr, len(cg.Contacts.External))
+	_ = d.Set(dataContactGroupIDAttr, cg.CID)
+	_ = d.Set(dataContactGroupNameAttr, cg.Name)
+	_ = d.Set(dataContactGroupUserCountAttr, len(cg.Contacts.Users))
+
+	if err := d.Set(dataContactGroupTagsAttr, cg.Tags); err != nil {
+		return fmt.Errorf("Unable to store contact group %q attribute: %w", dataContactGroupTagsAttr, err)
+	}
+
+	return nil
+}
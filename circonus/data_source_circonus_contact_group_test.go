@@ -0,0 +1,48 @@
+package circonus
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusContactGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusContactGroup,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCirconusContactGroupConfig + testAccDataSourceCirconusContactGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.circonus_contact_group.by_name", "id", "circonus_contact_group.staging-sev3", "id"),
+					resource.TestCheckResourceAttr("data.circonus_contact_group.by_name", "name", "ops-staging-sev3"),
+					resource.TestCheckResourceAttr("data.circonus_contact_group.by_name", "user_count", "0"),
+					resource.TestCheckResourceAttr("data.circonus_contact_group.by_name", "external_count", "0"),
+					resource.TestCheckResourceAttrPair("data.circonus_contact_group.by_id", "name", "circonus_contact_group.staging-sev3", "name"),
+				),
+			},
+			{
+				Config:      testAccDataSourceCirconusContactGroupNoMatchConfig,
+				ExpectError: regexp.MustCompile(`no contact group found with name`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusContactGroupConfig = `
+data "circonus_contact_group" "by_name" {
+  name = circonus_contact_group.staging-sev3.name
+}
+
+data "circonus_contact_group" "by_id" {
+  id = circonus_contact_group.staging-sev3.id
+}
+`
+
+const testAccDataSourceCirconusContactGroupNoMatchConfig = `
+data "circonus_contact_group" "no_match" {
+  name = "Terraform no such contact group"
+}
+`
@@ -0,0 +1,164 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	dataGraphDatapointCountAttr = "datapoint_count"
+	dataGraphDescriptionAttr    = "description"
+	dataGraphFirstMatchAttr     = "first_match"
+	dataGraphIDAttr             = "id"
+	dataGraphNotesAttr          = "notes"
+	dataGraphStyleAttr          = "graph_style"
+	dataGraphTagsAttr           = "tags"
+	dataGraphTitleAttr          = "title"
+	dataGraphTitleRegexAttr     = "title_regex"
+)
+
+var dataGraphDescription = map[schemaAttr]string{
+	dataGraphDatapointCountAttr: "The number of datapoints on the graph",
+	dataGraphDescriptionAttr:    "The description of the graph",
+	dataGraphFirstMatchAttr:     "Use the first matching graph instead of failing when more than one graph matches",
+	dataGraphIDAttr:             "The ID of the graph",
+	dataGraphNotesAttr:          "The notes of the graph",
+	dataGraphStyleAttr:          "The style of the graph",
+	dataGraphTagsAttr:           "Tags assigned to the graph",
+	dataGraphTitleAttr:          "The exact title of the graph",
+	dataGraphTitleRegexAttr:     "A regular expression matching the title of the graph",
+}
+
+func dataSourceCirconusGraph() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusGraphRead,
+
+		Schema: map[string]*schema.Schema{
+			dataGraphDatapointCountAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphDatapointCountAttr],
+			},
+			dataGraphDescriptionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphDescriptionAttr],
+			},
+			dataGraphFirstMatchAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: dataGraphDescription[dataGraphFirstMatchAttr],
+			},
+			dataGraphIDAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphIDAttr],
+			},
+			dataGraphNotesAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphNotesAttr],
+			},
+			dataGraphStyleAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphStyleAttr],
+			},
+			dataGraphTagsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: dataGraphDescription[dataGraphTagsAttr],
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			dataGraphTitleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{dataGraphTitleAttr, dataGraphTitleRegexAttr},
+				ValidateFunc: validateRegexp(dataGraphTitleAttr, `.+`),
+				Description:  dataGraphDescription[dataGraphTitleAttr],
+			},
+			dataGraphTitleRegexAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{dataGraphTitleAttr, dataGraphTitleRegexAttr},
+				ValidateFunc: validateFuncs(
+					validateRegexp(dataGraphTitleRegexAttr, `.+`),
+					validation.StringIsValidRegExp,
+				),
+				Description: dataGraphDescription[dataGraphTitleRegexAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusGraphRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	var graphs *[]api.Graph
+	var match func(title string) bool
+	var err error
+	var selector string
+	if v, ok := d.GetOk(dataGraphTitleAttr); ok {
+		title := v.(string)
+		selector = fmt.Sprintf("%s %q", dataGraphTitleAttr, title)
+		match = func(s string) bool { return s == title }
+
+		// the title filter narrows the search, the exact match is verified below
+		filter := api.SearchFilterType{"f_title": []string{title}}
+		graphs, err = ctxt.client.SearchGraphs(nil, &filter)
+	} else {
+		expr := d.Get(dataGraphTitleRegexAttr).(string)
+		selector = fmt.Sprintf("%s %q", dataGraphTitleRegexAttr, expr)
+		re, reErr := regexp.Compile(expr)
+		if reErr != nil {
+			return fmt.Errorf("unable to compile %s: %w", selector, reErr)
+		}
+		match = re.MatchString
+
+		graphs, err = ctxt.client.FetchGraphs()
+	}
+	if err != nil {
+		return err
+	}
+
+	matches := make([]api.Graph, 0, 1)
+	for _, g := range *graphs {
+		if match(g.Title) {
+			matches = append(matches, g)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("no graph found matching %s", selector)
+	case len(matches) > 1 && !d.Get(dataGraphFirstMatchAttr).(bool):
+		return fmt.Errorf("%d graphs match %s, use a more specific title or set %s", len(matches), selector, dataGraphFirstMatchAttr)
+	}
+
+	// graphs carry no timestamps, so the first match is the first graph
+	// returned by the API
+	g := matches[0]
+
+	d.SetId(g.CID)
+
+	_ = d.Set(dataGraphDatapointCountAttr, len(g.Datapoints))
+	_ = d.Set(dataGraphDescriptionAttr, g.Description)
+	_ = d.Set(dataGraphIDAttr, g.CID)
+	_ = d.Set(dataGraphNotesAttr, indirect(g.Notes))
+	_ = d.Set(dataGraphStyleAttr, indirect(g.Style))
+	_ = d.Set(dataGraphTitleAttr, g.Title)
+
+	if err := d.Set(dataGraphTagsAttr, g.Tags); err != nil {
+		return fmt.Errorf("Unable to store graph %q attribute: %w", dataGraphTagsAttr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusGraph(t *testing.T) {
+	graphConfig := fmt.Sprintf(testAccCirconusGraphConfigFmt, checkName, graphName, "")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusGraph,
+		Steps: []resource.TestStep{
+			{
+				Config: graphConfig + testAccDataSourceCirconusGraphConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.circonus_graph.by_title", "id", "circonus_graph.mixed-points", "id"),
+					resource.TestCheckResourceAttr("data.circonus_graph.by_title", "datapoint_count", "3"),
+					resource.TestCheckResourceAttr("data.circonus_graph.by_title", "description", "Terraform Test: mixed graph"),
+					resource.TestCheckResourceAttr("data.circonus_graph.by_title", "title", graphName),
+				),
+			},
+			{
+				Config:      graphConfig + testAccDataSourceCirconusGraphNoMatchConfig,
+				ExpectError: regexp.MustCompile(`no graph found matching title_regex`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusGraphConfig = `
+data "circonus_graph" "by_title" {
+  title = circonus_graph.mixed-points.name
+}
+`
+
+const testAccDataSourceCirconusGraphNoMatchConfig = `
+data "circonus_graph" "no_match" {
+  title_regex = "^Terraform no such graph [0-9a-f]{32}$"
+}
+`
@@ -0,0 +1,135 @@
+package circonus
+
+import (
+	"fmt"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	userAccountIDAttr = "id"
+	userAccountsAttr  = "accounts"
+	userCurrentAttr   = "current"
+	userEmailAttr     = "email"
+	userFirstnameAttr = "firstname"
+	userIDAttr        = "id"
+	userLastnameAttr  = "lastname"
+	userRoleAttr      = "role"
+)
+
+var userDescription = map[schemaAttr]string{
+	userAccountsAttr:  "The accounts the user is a member of, with the role of the user in each",
+	userCurrentAttr:   "Use the user owning the API token",
+	userEmailAttr:     "The email address of the user",
+	userFirstnameAttr: "The first name of the user",
+	userIDAttr:        "The Circonus ID of the user",
+	userLastnameAttr:  "The last name of the user",
+	userRoleAttr:      "The role of the user in the current account",
+}
+
+func dataSourceCirconusUser() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCirconusUserRead,
+
+		Schema: map[string]*schema.Schema{
+			userAccountsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: userDescription[userAccountsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						userAccountIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						userRoleAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			userCurrentAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{userIDAttr},
+				Description:   userDescription[userCurrentAttr],
+			},
+			userEmailAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: userDescription[userEmailAttr],
+			},
+			userFirstnameAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: userDescription[userFirstnameAttr],
+			},
+			userIDAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{userCurrentAttr},
+				ValidateFunc: validateFuncs(
+					validateRegexp(userIDAttr, config.UserCIDRegex),
+				),
+				Description: userDescription[userIDAttr],
+			},
+			userLastnameAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: userDescription[userLastnameAttr],
+			},
+			userRoleAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: userDescription[userRoleAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusUserRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*providerContext)
+
+	var cid string
+	if v, ok := d.GetOk(userIDAttr); ok {
+		cid = v.(string)
+	}
+
+	if v, ok := d.GetOk(userCurrentAttr); ok {
+		if v.(bool) {
+			cid = ""
+		}
+	}
+
+	u, err := c.client.FetchUser(api.CIDType(&cid))
+	if err != nil {
+		return err
+	}
+
+	accountsList := make([]interface{}, 0, len(u.Accounts))
+	for i := range u.Accounts {
+		accountsList = append(accountsList, map[string]interface{}{
+			userAccountIDAttr: u.Accounts[i].AccountCID,
+			userRoleAttr:      u.Accounts[i].Role,
+		})
+	}
+
+	d.SetId(u.CID)
+
+	if err := d.Set(userAccountsAttr, accountsList); err != nil {
+		return fmt.Errorf("Unable to store user %q attribute: %w", userAccountsAttr, err)
+	}
+
+	_ = d.Set(userEmailAttr, u.Email)
+	_ = d.Set(userFirstnameAttr, u.Firstname)
+	_ = d.Set(userIDAttr, u.CID)
+	_ = d.Set(userLastnameAttr, u.Lastname)
+	_ = d.Set(userRoleAttr, u.Role)
+
+	return nil
+}
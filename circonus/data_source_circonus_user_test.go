@@ -0,0 +1,30 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusUser(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceCirconusUserCurrentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.circonus_user.by_current", "id"),
+					resource.TestCheckResourceAttrSet("data.circonus_user.by_current", "email"),
+					resource.TestCheckResourceAttrSet("data.circonus_user.by_current", "accounts.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceCirconusUserCurrentConfig = `
+data "circonus_user" "by_current" {
+  current = true
+}
+`
@@ -5,6 +5,7 @@ package circonus
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
@@ -36,10 +37,20 @@ func (m *circonusMetric) ParseConfig(id string, d *schema.ResourceData) error {
 		m.Status = metricActiveToAPIStatus(v.(bool))
 	}
 
+	if v, found := d.GetOk(metricTagsAttr); found {
+		m.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+		sort.Strings(m.Tags)
+	}
+
 	if v, found := d.GetOk(metricTypeAttr); found {
 		m.Type = v.(string)
 	}
 
+	if v, found := d.GetOk(metricUnitsAttr); found {
+		units := v.(string)
+		m.Units = &units
+	}
+
 	return nil
 }
 
@@ -54,10 +65,22 @@ func (m *circonusMetric) ParseConfigMap(id string, attrMap map[string]interface{
 		m.Status = metricActiveToAPIStatus(v.(bool))
 	}
 
+	if v, found := attrMap[metricTagsAttr]; found {
+		if s, ok := v.(*schema.Set); ok && s.Len() > 0 {
+			m.Tags = derefStringList(flattenSet(s))
+			sort.Strings(m.Tags)
+		}
+	}
+
 	if v, found := attrMap[metricTypeAttr]; found {
 		m.Type = v.(string)
 	}
 
+	if v, found := attrMap[metricUnitsAttr]; found && v.(string) != "" {
+		units := v.(string)
+		m.Units = &units
+	}
+
 	return nil
 }
 
@@ -66,8 +89,13 @@ func (m *circonusMetric) SaveState(d *schema.ResourceData) error {
 
 	_ = d.Set(metricActiveAttr, metricAPIStatusToBool(m.Status))
 	_ = d.Set(metricNameAttr, m.Name)
+	_ = d.Set(metricTagsAttr, tagsToState(apiToTags(m.Tags)))
 	_ = d.Set(metricTypeAttr, m.Type)
 
+	if m.Units != nil {
+		_ = d.Set(metricUnitsAttr, *m.Units)
+	}
+
 	return nil
 }
 
@@ -3,9 +3,17 @@ package circonus
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -16,6 +24,7 @@ const (
 	defaultCirconus404ErrorString        = "API response code 404:"
 	defaultCirconusAggregationWindow     = "300s"
 	defaultCirconusAlertMinEscalateAfter = "300s"
+	defaultCirconusBrokerCacheTTL        = "60s"
 	defaultCirconusCheckPeriodMax        = "300s"
 	defaultCirconusCheckPeriodMin        = "10s"
 	defaultCirconusHTTPFormat            = "json"
@@ -28,15 +37,26 @@ const (
 )
 
 var providerDescription = map[string]string{
-	providerAPIURLAttr:  "URL of the Circonus API",
-	providerAutoTagAttr: "Signals that the provider should automatically add a tag to all API calls denoting that the resource was created by Terraform",
-	providerKeyAttr:     "API token used to authenticate with the Circonus API",
+	providerAPICAFileAttr:    "Path to a PEM encoded CA bundle used to verify the Circonus API certificate",
+	providerAPIProxyURLAttr:  "URL of the proxy used to reach the Circonus API, overrides the HTTP(S)_PROXY environment variables",
+	providerAPITokenEnvAttr:  "Name of the environment variable holding the API token, an alternative to key",
+	providerAPITokenFileAttr: "Path to a file holding the API token, an alternative to key",
+	providerAPIURLAttr:       "URL of the Circonus API",
+	providerAutoTagAttr:      "Signals that the provider should automatically add a tag to all API calls denoting that the resource was created by Terraform",
+	providerDefaultTagsAttr:  "Tags added to every resource managed by the provider which supports tags, the resource's own tags take precedence within the same category",
+	providerKeyAttr:          "API token used to authenticate with the Circonus API",
+	providerTLSInsecureAttr:  "Skip verification of the Circonus API certificate",
 }
 
 // Constants that want to be a constant but can't in Go
 var (
-	validContactHTTPFormats = validStringValues{"json", "params"}
-	validContactHTTPMethods = validStringValues{"GET", "POST"}
+	validCheckHTTPMethods          = validStringValues{"DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT"}
+	validCheckSNMPAuthProtocols    = validStringValues{"MD5", "SHA"}
+	validCheckSNMPPrivacyProtocols = validStringValues{"AES", "AES128", "DES"}
+	validCheckSNMPSecurityLevels   = validStringValues{"authNoPriv", "authPriv", "noAuthNoPriv"}
+	validCheckSNMPVersions         = validStringValues{"1", "2c", "3"}
+	validContactHTTPFormats        = validStringValues{"json", "params"}
+	validContactHTTPMethods        = validStringValues{"GET", "POST"}
 )
 
 type contactMethods string
@@ -58,12 +78,39 @@ type providerContext struct {
 
 	// defaultTag make up the tag to be used when autoTag tags a tag.
 	defaultTag circonusTag
+
+	// defaultTags are merged into the tags of every resource, see
+	// tagsWithDefaults
+	defaultTags []string
 }
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
 	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			providerAPICAFileAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_API_CA_FILE", ""),
+				Description: providerDescription[providerAPICAFileAttr],
+			},
+			providerAPIProxyURLAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateHTTPURL(providerAPIProxyURLAttr, urlIsAbs|urlOptional),
+				Description:  providerDescription[providerAPIProxyURLAttr],
+			},
+			providerAPITokenEnvAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(providerAPITokenEnvAttr, `^[A-Za-z_][A-Za-z0-9_]*$`),
+				Description:  providerDescription[providerAPITokenEnvAttr],
+			},
+			providerAPITokenFileAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: providerDescription[providerAPITokenFileAttr],
+			},
 			providerAPIURLAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -76,28 +123,57 @@ func Provider() *schema.Provider {
 				Default:     defaultAutoTag,
 				Description: providerDescription[providerAutoTagAttr],
 			},
+			providerDefaultTagsAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateTag,
+				},
+				Description: providerDescription[providerDefaultTagsAttr],
+			},
 			providerKeyAttr: {
+				// not defaulted from CIRCONUS_API_TOKEN in the schema, see
+				// providerAPIToken
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_API_TOKEN", nil),
 				Description: providerDescription[providerKeyAttr],
 			},
+			providerTLSInsecureAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: providerDescription[providerTLSInsecureAttr],
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"circonus_account":   dataSourceCirconusAccount(),
-			"circonus_collector": dataSourceCirconusCollector(),
+			"circonus_account":       dataSourceCirconusAccount(),
+			"circonus_alerts":        dataSourceCirconusAlerts(),
+			"circonus_annotation":    dataSourceCirconusAnnotation(),
+			"circonus_broker":        dataSourceCirconusBroker(),
+			"circonus_caql":          dataSourceCirconusCAQL(),
+			"circonus_check":         dataSourceCirconusCheck(),
+			"circonus_check_metrics": dataSourceCirconusCheckMetrics(),
+			"circonus_collector":     dataSourceCirconusCollector(),
+			"circonus_contact_group": dataSourceCirconusContactGroup(),
+			"circonus_collectors":    dataSourceCirconusCollectors(),
+			"circonus_graph":         dataSourceCirconusGraph(),
+			"circonus_user":          dataSourceCirconusUser(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"circonus_check":          resourceCheck(),
+			"circonus_check_metrics":  resourceCheckMetrics(),
 			"circonus_contact_group":  resourceContactGroup(),
 			"circonus_graph":          resourceGraph(),
+			"circonus_outlier_report": resourceOutlierReport(),
 			"circonus_overlay_set":    resourceOverlaySet(),
 			"circonus_dashboard":      resourceDashboard(),
 			"circonus_maintenance":    resourceMaintenance(),
 			"circonus_metric":         resourceMetric(),
+			"circonus_metric_cluster": resourceMetricCluster(),
 			"circonus_rule_set":       resourceRuleSet(),
 			"circonus_rule_set_group": resourceRuleSetGroup(),
 			"circonus_worksheet":      resourceWorksheet(),
@@ -118,10 +194,29 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		debug = true
 	}
 
+	var diags diag.Diagnostics
+
+	token, err := providerAPIToken(
+		d.Get(providerKeyAttr).(string),
+		d.Get(providerAPITokenFileAttr).(string),
+		d.Get(providerAPITokenEnvAttr).(string),
+	)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error initializing Circonus",
+			Detail:   fmt.Sprintf("Unable to configure Circonus API token: %s", err),
+		})
+		return nil, diags
+	}
+
 	config := &api.Config{
 		URL:      d.Get(providerAPIURLAttr).(string),
-		TokenKey: d.Get(providerKeyAttr).(string),
+		TokenKey: token,
 		TokenApp: "terraform-provider-circonus",
+		// the same brokers are resolved repeatedly during an apply (e.g. by
+		// every circonus_collector data source) and rarely change
+		BrokerCacheTTL: defaultCirconusBrokerCacheTTL,
 	}
 
 	if debug {
@@ -135,7 +230,20 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		config.Log = log.New(log.Writer(), "", log.LstdFlags)
 	}
 
-	var diags diag.Diagnostics
+	httpClient, err := providerHTTPClient(
+		d.Get(providerAPICAFileAttr).(string),
+		d.Get(providerAPIProxyURLAttr).(string),
+		d.Get(providerTLSInsecureAttr).(bool),
+	)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error initializing Circonus",
+			Detail:   fmt.Sprintf("Unable to configure Circonus API HTTP client: %s", err),
+		})
+		return nil, diags
+	}
+	config.HTTPClient = httpClient
 
 	client, err := api.NewAPI(config)
 	if err != nil {
@@ -150,8 +258,109 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	client.EnableExponentialBackoff()
 
 	return &providerContext{
-		client:     client,
-		autoTag:    d.Get(providerAutoTagAttr).(bool),
-		defaultTag: defaultCirconusTag,
+		client:      client,
+		autoTag:     d.Get(providerAutoTagAttr).(bool),
+		defaultTag:  defaultCirconusTag,
+		defaultTags: derefStringList(flattenSet(d.Get(providerDefaultTagsAttr).(*schema.Set))),
 	}, diags
 }
+
+// providerAPIToken returns the API token from exactly one of the key, the
+// token file, or the environment variable named by tokenEnv.  When none of
+// them is set the CIRCONUS_API_TOKEN environment variable is used.  The token
+// is read at configure time and is never logged, errors only name its source.
+func providerAPIToken(key, tokenFile, tokenEnv string) (string, error) {
+	var set []string
+	if key != "" {
+		set = append(set, providerKeyAttr)
+	}
+	if tokenFile != "" {
+		set = append(set, providerAPITokenFileAttr)
+	}
+	if tokenEnv != "" {
+		set = append(set, providerAPITokenEnvAttr)
+	}
+	if len(set) > 1 {
+		return "", fmt.Errorf("only one of %s, %s and %s can be set, got %s", providerKeyAttr, providerAPITokenFileAttr, providerAPITokenEnvAttr, strings.Join(set, ", "))
+	}
+
+	var token, source string
+	switch {
+	case key != "":
+		token, source = key, providerKeyAttr
+	case tokenFile != "":
+		buf, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read %s %q: %w", providerAPITokenFileAttr, tokenFile, err)
+		}
+		token, source = strings.TrimSpace(string(buf)), fmt.Sprintf("%s %q", providerAPITokenFileAttr, tokenFile)
+	case tokenEnv != "":
+		token, source = strings.TrimSpace(os.Getenv(tokenEnv)), fmt.Sprintf("%s %q", providerAPITokenEnvAttr, tokenEnv)
+	default:
+		token, source = strings.TrimSpace(os.Getenv("CIRCONUS_API_TOKEN")), "CIRCONUS_API_TOKEN"
+	}
+
+	if token == "" {
+		if source == "CIRCONUS_API_TOKEN" {
+			return "", fmt.Errorf("one of %s, %s or %s is required (or set CIRCONUS_API_TOKEN)", providerKeyAttr, providerAPITokenFileAttr, providerAPITokenEnvAttr)
+		}
+		return "", fmt.Errorf("API token from %s is empty", source)
+	}
+
+	log.Printf("[DEBUG] using Circonus API token from %s", source)
+
+	return token, nil
+}
+
+// providerHTTPClient returns an http client configured with the provider's
+// transport settings, or nil when none are set so the API client uses its
+// default transport.  An explicit proxy URL takes precedence over the
+// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables, which are
+// used otherwise.
+func providerHTTPClient(caFile, proxyURL string, insecure bool) (*http.Client, error) {
+	if caFile == "" && proxyURL == "" && !insecure {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure, // nolint: gosec
+	}
+
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s %q: %w", providerAPICAFileAttr, caFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s %q", providerAPICAFileAttr, caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s %q: %w", providerAPIProxyURLAttr, proxyURL, err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: proxy,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:     tlsConfig,
+			DisableKeepAlives:   true,
+			MaxIdleConnsPerHost: -1,
+			DisableCompression:  true,
+		},
+	}, nil
+}
@@ -1,9 +1,16 @@
 package circonus
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
+	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -27,6 +34,150 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProviderHTTPClient(t *testing.T) {
+	client, err := providerHTTPClient("", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if client != nil {
+		t.Fatal("expected nil client when no transport settings are given")
+	}
+
+	client, err = providerHTTPClient("", "http://proxy.example.com:3128", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	transport := client.Transport.(*http.Transport)
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Fatal("expected InsecureSkipVerify to be set")
+	}
+	req, _ := http.NewRequest("GET", "https://api.circonus.com/v2/account/current", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if proxyURL == nil || proxyURL.Host != "proxy.example.com:3128" {
+		t.Fatalf("expected explicit proxy, got %v", proxyURL)
+	}
+
+	if _, err := providerHTTPClient("/nonexistent/ca.pem", "", false); err == nil {
+		t.Fatal("expected error for missing CA file")
+	}
+}
+
+func TestProviderAPIToken(t *testing.T) {
+	tokenFile, err := ioutil.TempFile("", "circonus-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.Remove(tokenFile.Name())
+	if _, err := tokenFile.WriteString("file-token\n"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tokenFile.Close()
+
+	os.Setenv("TF_CIRCONUS_TEST_TOKEN", "env-token")
+	defer os.Unsetenv("TF_CIRCONUS_TEST_TOKEN")
+
+	tests := []struct {
+		key, file, env string
+		token          string
+		err            string
+	}{
+		{key: "key-token", token: "key-token"},
+		{file: tokenFile.Name(), token: "file-token"},
+		{env: "TF_CIRCONUS_TEST_TOKEN", token: "env-token"},
+		{key: "key-token", env: "TF_CIRCONUS_TEST_TOKEN", err: "only one of"},
+		{file: "/nonexistent/token", err: "unable to read"},
+		{env: "TF_CIRCONUS_TEST_UNSET", err: "is empty"},
+	}
+
+	for _, test := range tests {
+		token, err := providerAPIToken(test.key, test.file, test.env)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%+v: expected error containing %q, got %v", test, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %s", test, err)
+			continue
+		}
+		if token != test.token {
+			t.Errorf("%+v: expected token %q, got %q", test, test.token, token)
+		}
+	}
+}
+
+func TestProviderAPIURL(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/staging/v2/user") {
+			_, _ = w.Write([]byte(`{"_cid":"/user/1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	os.Setenv("CIRCONUS_API_URL", "http://env.example.invalid/v2")
+	defer os.Unsetenv("CIRCONUS_API_URL")
+
+	tests := []struct {
+		raw map[string]interface{}
+		url string
+	}{
+		{map[string]interface{}{"key": "test-token", "api_url": srv.URL + "/staging/v2"}, srv.URL + "/staging/v2"},
+		{map[string]interface{}{"key": "test-token"}, "http://env.example.invalid/v2"},
+	}
+
+	for _, test := range tests {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, test.raw)
+		if v := d.Get(providerAPIURLAttr).(string); v != test.url {
+			t.Errorf("%v: expected %s %q, got %q", test.raw, providerAPIURLAttr, test.url, v)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, tests[0].raw)
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	client := meta.(*providerContext).client
+
+	if _, err := client.FetchMaintenanceWindows(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.FetchAnnotations(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.FetchUser(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"/staging/v2/maintenance", "/staging/v2/annotation", "/staging/v2/user/current"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected request paths %q, got %q", expected, paths)
+	}
+}
+
+// testAPIProviderContext returns a provider context whose client sends its
+// requests to a test server handled by handler, the caller closes the server.
+func testAPIProviderContext(t *testing.T, handler http.HandlerFunc) (*providerContext, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+
+	client, err := api.NewAPI(&api.Config{URL: srv.URL, TokenKey: "test-token"})
+	if err != nil {
+		srv.Close()
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return &providerContext{client: client}, srv
+}
+
 func testAccPreCheck(t *testing.T) {
 	if apiToken := os.Getenv("CIRCONUS_API_TOKEN"); apiToken == "" {
 		t.Fatal("CIRCONUS_API_TOKEN must be set for acceptance tests")
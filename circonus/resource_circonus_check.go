@@ -19,6 +19,8 @@ package circonus
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
@@ -28,38 +30,44 @@ import (
 
 const (
 	// circonus_check.* global resource attribute names
-	checkActiveAttr       = "active"
-	checkCAQLAttr         = "caql"
-	checkCloudWatchAttr   = "cloudwatch"
-	checkCollectorAttr    = "collector"
-	checkConsulAttr       = "consul"
-	checkDNSAttr          = "dns"
-	checkExternalAttr     = "external"
-	checkHTTPAttr         = "http"
-	checkHTTPTrapAttr     = "httptrap"
-	checkICMPPingAttr     = "icmp_ping"
-	checkJMXAttr          = "jmx"
-	checkJSONAttr         = "json"
-	checkMemcachedAttr    = "memcached"
-	checkMetricAttr       = "metric"
-	checkMetricFilterAttr = "metric_filter"
-	checkMetricLimitAttr  = "metric_limit"
-	checkMySQLAttr        = "mysql"
-	checkNameAttr         = "name"
-	checkNTPAttr          = "ntp"
-	checkNotesAttr        = "notes"
-	checkPeriodAttr       = "period"
-	checkPostgreSQLAttr   = "postgresql"
-	checkPromTextAttr     = "promtext"
-	checkRedisAttr        = "redis"
-	checkSMTPAttr         = "smtp"
-	checkSNMPAttr         = "snmp"
-	checkStatsdAttr       = "statsd"
-	checkTCPAttr          = "tcp"
-	checkTagsAttr         = "tags"
-	checkTargetAttr       = "target"
-	checkTimeoutAttr      = "timeout"
-	checkTypeAttr         = "type"
+	checkActiveAttr          = "active"
+	checkCAQLAttr            = "caql"
+	checkCloudWatchAttr      = "cloudwatch"
+	checkCollectorAttr       = "collector"
+	checkCompositeAttr       = "composite"
+	checkConsulAttr          = "consul"
+	checkDNSAttr             = "dns"
+	checkExternalAttr        = "external"
+	checkExternalMetricsAttr = "external_metrics"
+	checkHTTPAttr            = "http"
+	checkHTTPTrapAttr        = "httptrap"
+	checkICMPPingAttr        = "icmp_ping"
+	checkInheritTagsAttr     = "inherit_tags"
+	checkJMXAttr             = "jmx"
+	checkJSONAttr            = "json"
+	checkMemcachedAttr       = "memcached"
+	checkMetricAttr          = "metric"
+	checkMetricFilterAttr    = "metric_filter"
+	checkMetricLimitAttr     = "metric_limit"
+	checkMySQLAttr           = "mysql"
+	checkNADAttr             = "nad"
+	checkNameAttr            = "name"
+	checkNTPAttr             = "ntp"
+	checkNormalizeTargetAttr = "normalize_target"
+	checkNotesAttr           = "notes"
+	checkPeriodAttr          = "period"
+	checkPostgreSQLAttr      = "postgresql"
+	checkPromTextAttr        = "promtext"
+	checkRedisAttr           = "redis"
+	checkSMTPAttr            = "smtp"
+	checkSNMPAttr            = "snmp"
+	checkStatsdAttr          = "statsd"
+	checkTCPAttr             = "tcp"
+	checkTagsAttr            = "tags"
+	checkTagsMapAttr         = "tags_map"
+	checkTargetAttr          = "target"
+	checkTimeoutAttr         = "timeout"
+	checkTypeAttr            = "type"
 
 	// circonus_check.collector.* resource attribute names
 	checkCollectorIDAttr = "id"
@@ -72,6 +80,9 @@ const (
 
 	// Out parameters for circonus_check
 	checkOutByCollectorAttr        = "check_by_collector"
+	checkOutCertMetricNamesAttr    = "cert_metric_names"
+	checkOutCheckInstancesAttr     = "check_instances"
+	checkOutCompositeMetricIDsAttr = "composite_metric_ids"
 	checkOutIDAttr                 = "check_id"
 	checkOutChecksAttr             = "checks"
 	checkOutCreatedAttr            = "created"
@@ -79,12 +90,18 @@ const (
 	checkOutLastModifiedByAttr     = "last_modified_by"
 	checkOutReverseConnectURLsAttr = "reverse_connect_urls"
 	checkOutCheckUUIDsAttr         = "uuids"
+
+	// circonus_check.check_instances.* out parameter names
+	checkInstanceCheckIDAttr     = "check_id"
+	checkInstanceCollectorIDAttr = "collector_id"
+	checkInstanceUUIDAttr        = "uuid"
 )
 
 const (
 	// Circonus API constants from their API endpoints
 	apiCheckTypeCAQLAttr       apiCheckType = "caql"
 	apiCheckTypeCloudWatchAttr apiCheckType = "cloudwatch"
+	apiCheckTypeCompositeAttr  apiCheckType = "composite"
 	apiCheckTypeConsulAttr     apiCheckType = "consul"
 	apiCheckTypeDNSAttr        apiCheckType = "dns"
 	apiCheckTypeExternalAttr   apiCheckType = "external"
@@ -95,6 +112,7 @@ const (
 	apiCheckTypeICMPPingAttr   apiCheckType = "ping_icmp"
 	apiCheckTypeJSONAttr       apiCheckType = "json"
 	apiCheckTypeMySQLAttr      apiCheckType = "mysql"
+	apiCheckTypeNADAttr        apiCheckType = "json,nad"
 	apiCheckTypeNTPAttr        apiCheckType = "ntp"
 	apiCheckTypePostgreSQLAttr apiCheckType = "postgres"
 	apiCheckTypePromTextAttr   apiCheckType = "promtext"
@@ -106,40 +124,49 @@ const (
 )
 
 var checkDescriptions = attrDescrs{
-	checkActiveAttr:       "If the check is activate or disabled",
-	checkCAQLAttr:         "CAQL check configuration",
-	checkCloudWatchAttr:   "CloudWatch check configuration",
-	checkCollectorAttr:    "The collector(s) that are responsible for gathering the metrics",
-	checkConsulAttr:       "Consul check configuration",
-	checkDNSAttr:          "DNS check configuration",
-	checkExternalAttr:     "External check configuration",
-	checkHTTPAttr:         "HTTP check configuration",
-	checkHTTPTrapAttr:     "HTTP Trap check configuration",
-	checkICMPPingAttr:     "ICMP ping check configuration",
-	checkJMXAttr:          "JMX check configuration",
-	checkJSONAttr:         "JSON check configuration",
-	checkMemcachedAttr:    "Memcached check configuration",
-	checkMetricAttr:       "Configuration for a stream of metrics",
-	checkMetricFilterAttr: "Allow/deny configuration for regex based metric ingestion",
-	checkMetricLimitAttr:  `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer)`,
-	checkMySQLAttr:        "MySQL check configuration",
-	checkNameAttr:         "The name of the check bundle that will be displayed in the web interface",
-	checkNTPAttr:          "NTP check configuration",
-	checkNotesAttr:        "Notes about this check bundle",
-	checkPeriodAttr:       "The period between each time the check is made",
-	checkPostgreSQLAttr:   "PostgreSQL check configuration",
-	checkPromTextAttr:     "Prometheus URL scraper check configuration",
-	checkSMTPAttr:         "SMTP check configuration",
-	checkRedisAttr:        "Redis check configuration",
-	checkSNMPAttr:         "SNMP check configuration",
-	checkStatsdAttr:       "statsd check configuration",
-	checkTCPAttr:          "TCP check configuration",
-	checkTagsAttr:         "A list of tags assigned to the check",
-	checkTargetAttr:       "The target of the check (e.g. hostname, URL, IP, etc)",
-	checkTimeoutAttr:      "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
-	checkTypeAttr:         "The check type",
+	checkActiveAttr:          "If the check is active, a disabled check stops collecting data but keeps its configuration and history",
+	checkCAQLAttr:            "CAQL check configuration",
+	checkCloudWatchAttr:      "CloudWatch check configuration",
+	checkCollectorAttr:       "The collector(s) that are responsible for gathering the metrics",
+	checkCompositeAttr:       "Composite check configuration",
+	checkConsulAttr:          "Consul check configuration",
+	checkDNSAttr:             "DNS check configuration",
+	checkExternalAttr:        "External check configuration",
+	checkExternalMetricsAttr: "The metrics of the check are managed by a circonus_check_metrics resource and left untouched by this resource",
+	checkHTTPAttr:            "HTTP check configuration",
+	checkHTTPTrapAttr:        "HTTP Trap check configuration",
+	checkICMPPingAttr:        "ICMP ping check configuration",
+	checkInheritTagsAttr:     "Merge the check's tags into the tags of each metric, metric tags take precedence within the same category",
+	checkJMXAttr:             "JMX check configuration",
+	checkJSONAttr:            "JSON check configuration",
+	checkMemcachedAttr:       "Memcached check configuration",
+	checkMetricAttr:          "Configuration for a stream of metrics",
+	checkMetricFilterAttr:    "Allow/deny configuration for regex based metric ingestion",
+	checkMetricLimitAttr:     `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer) to be activated automatically`,
+	checkMySQLAttr:           "MySQL check configuration",
+	checkNADAttr:             "Node Agent (NAD) check configuration",
+	checkNameAttr:            "The name of the check bundle that will be displayed in the web interface",
+	checkNTPAttr:             "NTP check configuration",
+	checkNormalizeTargetAttr: "Ignore differences in case and a trailing dot between the configured and the stored hostname target",
+	checkNotesAttr:           "Notes about this check bundle",
+	checkPeriodAttr:          "The period between each time the check is made",
+	checkPostgreSQLAttr:      "PostgreSQL check configuration",
+	checkPromTextAttr:        "Prometheus URL scraper check configuration",
+	checkSMTPAttr:            "SMTP check configuration",
+	checkRedisAttr:           "Redis check configuration",
+	checkSNMPAttr:            "SNMP check configuration",
+	checkStatsdAttr:          "statsd check configuration",
+	checkTCPAttr:             "TCP check configuration",
+	checkTagsAttr:            "A list of tags assigned to the check",
+	checkTagsMapAttr:         "The tags assigned to the check as a map of category to value, instead of tags",
+	checkTargetAttr:          "The target of the check (e.g. hostname, URL, IP, etc)",
+	checkTimeoutAttr:         "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
+	checkTypeAttr:            "The check type",
 
 	checkOutByCollectorAttr:        "",
+	checkOutCertMetricNamesAttr:    "The names of the certificate metrics (e.g. cert_end_in) of an http check of an https URL or a tcp check using TLS",
+	checkOutCheckInstancesAttr:     "The check instances of the check, one per collector",
+	checkOutCompositeMetricIDsAttr: "The IDs of the composite metric of a composite check, one per check instance",
 	checkOutCheckUUIDsAttr:         "",
 	checkOutChecksAttr:             "",
 	checkOutCreatedAttr:            "",
@@ -153,6 +180,12 @@ var checkCollectorDescriptions = attrDescrs{
 	checkCollectorIDAttr: "The ID of the collector",
 }
 
+var checkInstanceDescriptions = attrDescrs{
+	checkInstanceCheckIDAttr:     "The ID of the check instance",
+	checkInstanceCollectorIDAttr: "The ID of the collector running the check instance",
+	checkInstanceUUIDAttr:        "The UUID of the check instance",
+}
+
 var checkMetricDescriptions = metricDescriptions
 var checkMetricFilterDescriptions = attrDescrs{
 	"type":      "'allow' or 'deny'",
@@ -194,6 +227,7 @@ func resourceCheck() *schema.Resource {
 					}),
 				},
 			},
+			checkCompositeAttr: schemaCheckComposite,
 			checkConsulAttr:    schemaCheckConsul,
 			checkDNSAttr:       schemaCheckDNS,
 			checkExternalAttr:  schemaCheckExternal,
@@ -202,8 +236,20 @@ func resourceCheck() *schema.Resource {
 			checkICMPPingAttr:  schemaCheckICMPPing,
 			checkJMXAttr:       schemaCheckJMX,
 			checkMemcachedAttr: schemaCheckMemcached,
+			checkNADAttr:       schemaCheckNAD,
 			checkNTPAttr:       schemaCheckNTP,
 			checkJSONAttr:      schemaCheckJSON,
+			checkExternalMetricsAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{checkMetricAttr, checkMetricFilterAttr},
+			},
+			checkInheritTagsAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			checkMetricAttr: {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -220,11 +266,17 @@ func resourceCheck() *schema.Resource {
 							Required:     true,
 							ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
 						},
+						metricTagsAttr: tagMakeConfigSchema(metricTagsAttr),
 						metricTypeAttr: {
 							Type:         schema.TypeString,
 							Required:     true,
 							ValidateFunc: validateMetricType,
 						},
+						metricUnitsAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(metricUnitsAttr, metricUnitsRegexp),
+						},
 					}),
 				},
 			},
@@ -270,6 +322,11 @@ func resourceCheck() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			checkNormalizeTargetAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			checkNotesAttr: {
 				Type:      schema.TypeString,
 				Optional:  true,
@@ -293,11 +350,13 @@ func resourceCheck() *schema.Resource {
 			checkSNMPAttr:       schemaCheckSNMP,
 			checkStatsdAttr:     schemaCheckStatsd,
 			checkTagsAttr:       tagMakeConfigSchema(checkTagsAttr),
+			checkTagsMapAttr:    tagMakeMapConfigSchema(checkTagsAttr),
 			checkTargetAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Computed:     true,
-				ValidateFunc: validateRegexp(checkTargetAttr, `.+`),
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateRegexp(checkTargetAttr, `.+`),
+				DiffSuppressFunc: suppressEquivalentCheckTargets,
 			},
 			checkTCPAttr: schemaCheckTCP,
 			checkTimeoutAttr: {
@@ -330,6 +389,40 @@ func resourceCheck() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			checkOutCertMetricNamesAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			checkOutCheckInstancesAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkInstanceDescriptions, map[schemaAttr]*schema.Schema{
+						checkInstanceCheckIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						checkInstanceCollectorIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						checkInstanceUUIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					}),
+				},
+			},
+			checkOutCompositeMetricIDsAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			checkOutCheckUUIDsAttr: {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -374,6 +467,8 @@ func checkCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing check schema during create: %w", err)
 	}
 
+	c.Tags = ctxt.tagsWithDefaults(c.Tags)
+
 	if err := c.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating check: %w", err)
 	}
@@ -407,6 +502,11 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	c, err := loadCheck(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -416,8 +516,21 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 	// type specific attributes handled below in their respective checkRead*().
 
 	checkIDsByCollector := make(map[string]interface{}, len(c.Checks))
+	checkInstances := make([]interface{}, 0, len(c.Checks))
 	for i, b := range c.Brokers {
+		if i >= len(c.Checks) {
+			break
+		}
 		checkIDsByCollector[b] = c.Checks[i]
+
+		instance := map[string]interface{}{
+			string(checkInstanceCheckIDAttr):     c.Checks[i],
+			string(checkInstanceCollectorIDAttr): b,
+		}
+		if i < len(c.CheckUUIDs) {
+			instance[string(checkInstanceUUIDAttr)] = c.CheckUUIDs[i]
+		}
+		checkInstances = append(checkInstances, instance)
 	}
 
 	var checkID string
@@ -425,14 +538,41 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 		checkID = c.Checks[0]
 	}
 
+	// When inheriting tags, the check's tags are removed from the metric tags
+	// again unless they are also configured on the metric, the state only
+	// holds the tags of the metric itself.
+	inheritTags := d.Get(checkInheritTagsAttr).(bool)
+	configuredMetricTags := checkConfiguredMetricTags(d)
+
+	// While a check is paused (active = false) the metrics keep the activation
+	// state last configured, so that re-enabling the check restores it, no
+	// matter how the API reports the metrics of a disabled check.
+	paused := !checkAPIStatusToBool(c.Status)
+	configuredMetricActive := checkConfiguredMetricActive(d)
+
 	metrics := make([]interface{}, 0)
 	for _, m := range c.Metrics {
+		metricTags := m.Tags
+		if inheritTags {
+			metricTags = tagsStripInherited(metricTags, c.Tags, configuredMetricTags[m.Name])
+		}
+
+		metricActive := metricAPIStatusToBool(m.Status)
+		if active, ok := configuredMetricActive[m.Name]; ok && paused {
+			metricActive = active
+		}
+
 		metricAttrs := map[string]interface{}{
-			string(metricActiveAttr): metricAPIStatusToBool(m.Status),
+			string(metricActiveAttr): metricActive,
 			string(metricNameAttr):   m.Name,
+			string(metricTagsAttr):   tagsToState(apiToTags(metricTags)),
 			string(metricTypeAttr):   m.Type,
 		}
 
+		if m.Units != nil {
+			metricAttrs[string(metricUnitsAttr)] = *m.Units
+		}
+
 		metrics = append(metrics, metricAttrs)
 	}
 
@@ -462,11 +602,19 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkCollectorAttr, err)
 	}
 
-	_ = d.Set(checkMetricLimitAttr, c.MetricLimit)
+	if c.MetricLimit != nil {
+		_ = d.Set(checkMetricLimitAttr, *c.MetricLimit)
+	}
 	_ = d.Set(checkNameAttr, c.DisplayName)
 	_ = d.Set(checkNotesAttr, c.Notes)
 	_ = d.Set(checkPeriodAttr, fmt.Sprintf("%ds", c.Period))
 
+	// the metrics of a check with external_metrics are stored by the
+	// circonus_check_metrics resource
+	if d.Get(checkExternalMetricsAttr).(bool) {
+		metrics = make([]interface{}, 0)
+	}
+
 	if err := d.Set(checkMetricAttr, metrics); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkMetricAttr, err)
 	}
@@ -475,7 +623,12 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkMetricFilterAttr, err)
 	}
 
-	if err := d.Set(checkTagsAttr, c.Tags); err != nil {
+	tags := ctxt.tagsWithoutDefaults(c.Tags, tagsFromConfig(d, checkTagsAttr, checkTagsMapAttr))
+	if tagsMapDeclared(d, checkTagsMapAttr) {
+		if err := d.Set(checkTagsMapAttr, tagsToMap(tags)); err != nil {
+			return fmt.Errorf("Unable to store check %q attribute: %w", checkTagsMapAttr, err)
+		}
+	} else if err := d.Set(checkTagsAttr, tags); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkTagsAttr, err)
 	}
 
@@ -488,6 +641,8 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 
 	_ = d.Set(checkTypeAttr, c.Type)
 
+	certMetricNames := c.CertMetricNames()
+
 	// Last step: parse a check_bundle's config into the statefile.
 	if err := parseCheckTypeConfig(&c, d); err != nil {
 		return fmt.Errorf("Unable to parse check config: %w", err)
@@ -498,6 +653,14 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutByCollectorAttr, err)
 	}
 
+	if err := d.Set(checkOutCertMetricNamesAttr, certMetricNames); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutCertMetricNamesAttr, err)
+	}
+
+	if err := d.Set(checkOutCheckInstancesAttr, checkInstances); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutCheckInstancesAttr, err)
+	}
+
 	if err := d.Set(checkOutCheckUUIDsAttr, c.CheckUUIDs); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutCheckUUIDsAttr, err)
 	}
@@ -528,6 +691,8 @@ func checkUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	c.Tags = ctxt.tagsWithDefaults(c.Tags)
+
 	c.CID = d.Id()
 	if err := c.Update(ctxt); err != nil {
 		return fmt.Errorf("unable to update check %q: %w", d.Id(), err)
@@ -551,9 +716,9 @@ func checkDelete(d *schema.ResourceData, meta interface{}) error {
 // ParseConfig reads Terraform config data and stores the information into a
 // Circonus CheckBundle object.
 func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
-	if v, found := d.GetOk(checkActiveAttr); found {
-		c.Status = checkActiveToAPIStatus(v.(bool))
-	}
+	// GetOk can not be used, it reports false as not found and the check
+	// would never be disabled
+	c.Status = checkActiveToAPIStatus(d.Get(checkActiveAttr).(bool))
 
 	if v, found := d.GetOk(checkCollectorAttr); found {
 		l := v.(*schema.Set).List()
@@ -568,8 +733,11 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
-	if v, found := d.GetOk(checkMetricLimitAttr); found {
-		c.MetricLimit = v.(int)
+	// GetOk can not be used, it reports 0 as not found and the metric limit
+	// could never be disabled
+	if v, found := d.GetOkExists(checkMetricLimitAttr); found { // nolint: staticcheck
+		metricLimit := v.(int)
+		c.MetricLimit = &metricLimit
 	}
 
 	if v, found := d.GetOk(checkNameAttr); found {
@@ -590,6 +758,8 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		c.Period = uint(d.Seconds())
 	}
 
+	c.externalMetrics = d.Get(checkExternalMetricsAttr).(bool)
+
 	if v, found := d.GetOk(checkMetricAttr); found {
 		metricList := v.([]interface{})
 		c.Metrics = make([]api.CheckBundleMetric, 0, len(metricList))
@@ -645,8 +815,12 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
-	if v, found := d.GetOk(checkTagsAttr); found {
-		c.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	c.Tags = tagsFromConfig(d, checkTagsAttr, checkTagsMapAttr)
+
+	if d.Get(checkInheritTagsAttr).(bool) {
+		for i := range c.Metrics {
+			c.Metrics[i].Tags = tagsMergeInherited(c.Metrics[i].Tags, c.Tags)
+		}
 	}
 
 	if v, found := d.GetOk(checkTargetAttr); found {
@@ -679,12 +853,63 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
+// checkConfiguredMetricTags returns the tags of each metric in the config (or
+// prior state), by metric name.
+func checkConfiguredMetricTags(d *schema.ResourceData) map[string][]string {
+	metricTags := make(map[string][]string)
+
+	metricList, ok := d.Get(checkMetricAttr).([]interface{})
+	if !ok {
+		return metricTags
+	}
+
+	for _, metricRaw := range metricList {
+		metricAttrs, ok := metricRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := metricAttrs[string(metricNameAttr)].(string)
+		if tags, ok := metricAttrs[string(metricTagsAttr)].(*schema.Set); ok {
+			metricTags[name] = derefStringList(flattenSet(tags))
+		}
+	}
+
+	return metricTags
+}
+
+// checkConfiguredMetricActive returns the configured activation state of each
+// metric of the check, keyed by metric name.
+func checkConfiguredMetricActive(d *schema.ResourceData) map[string]bool {
+	metricActive := make(map[string]bool)
+
+	metricList, ok := d.Get(checkMetricAttr).([]interface{})
+	if !ok {
+		return metricActive
+	}
+
+	for _, metricRaw := range metricList {
+		metricAttrs, ok := metricRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := metricAttrs[string(metricNameAttr)].(string)
+		if active, ok := metricAttrs[string(metricActiveAttr)].(bool); ok {
+			metricActive[name] = active
+		}
+	}
+
+	return metricActive
+}
+
 // checkConfigToAPI parses the Terraform config into the respective per-check
 // type api.Config attributes.
 func checkConfigToAPI(c *circonusCheck, d *schema.ResourceData) error {
 	checkTypeParseMap := map[string]func(*circonusCheck, interfaceList) error{
 		checkCAQLAttr:       checkConfigToAPICAQL,
 		checkCloudWatchAttr: checkConfigToAPICloudWatch,
+		checkCompositeAttr:  checkConfigToAPIComposite,
 		checkConsulAttr:     checkConfigToAPIConsul,
 		checkDNSAttr:        checkConfigToAPIDNS,
 		checkExternalAttr:   checkConfigToAPIExternal,
@@ -695,6 +920,7 @@ func checkConfigToAPI(c *circonusCheck, d *schema.ResourceData) error {
 		checkMemcachedAttr:  checkConfigToAPIMemcached,
 		checkJSONAttr:       checkConfigToAPIJSON,
 		checkMySQLAttr:      checkConfigToAPIMySQL,
+		checkNADAttr:        checkConfigToAPINAD,
 		checkNTPAttr:        checkConfigToAPINTP,
 		checkPostgreSQLAttr: checkConfigToAPIPostgreSQL,
 		checkPromTextAttr:   checkConfigToAPIPromText,
@@ -731,6 +957,7 @@ func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
 	checkTypeConfigHandlers := map[apiCheckType]func(*circonusCheck, *schema.ResourceData) error{
 		apiCheckTypeCAQLAttr:       checkAPIToStateCAQL,
 		apiCheckTypeCloudWatchAttr: checkAPIToStateCloudWatch,
+		apiCheckTypeCompositeAttr:  checkAPIToStateComposite,
 		apiCheckTypeConsulAttr:     checkAPIToStateConsul,
 		apiCheckTypeDNSAttr:        checkAPIToStateDNS,
 		apiCheckTypeExternalAttr:   checkAPIToStateExternal,
@@ -741,6 +968,7 @@ func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
 		apiCheckTypeMemcachedAttr:  checkAPIToStateMemcached,
 		apiCheckTypeJSONAttr:       checkAPIToStateJSON,
 		apiCheckTypeMySQLAttr:      checkAPIToStateMySQL,
+		apiCheckTypeNADAttr:        checkAPIToStateNAD,
 		apiCheckTypeNTPAttr:        checkAPIToStateNTP,
 		apiCheckTypePostgreSQLAttr: checkAPIToStatePostgreSQL,
 		apiCheckTypePromTextAttr:   checkAPIToStatePromText,
@@ -763,3 +991,29 @@ func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
 
 	return nil
 }
+
+// suppressEquivalentCheckTargets suppresses the diff between the configured
+// target and the target stored by the API when the API only normalized a
+// hostname, see checkTargetsEquivalent.  Disabled by normalize_target = false.
+func suppressEquivalentCheckTargets(k, old, new string, d *schema.ResourceData) bool {
+	if !d.Get(checkNormalizeTargetAttr).(bool) {
+		return false
+	}
+
+	return checkTargetsEquivalent(old, new)
+}
+
+// checkTargetsEquivalent reports whether two targets name the same host,
+// ignoring case and a trailing dot.  IP addresses are never normalized and
+// only equal when identical.
+func checkTargetsEquivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	if net.ParseIP(a) != nil || net.ParseIP(b) != nil {
+		return false
+	}
+
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
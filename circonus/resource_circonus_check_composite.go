@@ -0,0 +1,191 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.composite.* resource attribute names
+	checkCompositeFormulaAttr    = "formula"
+	checkCompositeMetricNameAttr = "metric_name"
+)
+
+var checkCompositeDescriptions = attrDescrs{
+	checkCompositeFormulaAttr:    `The formula computing the composite metric, other metrics are referenced as metric:<function>("<check uuid>", "<metric name>")`,
+	checkCompositeMetricNameAttr: "The name of the composite metric, must match the name of a metric of the check",
+}
+
+// checkCompositeMetricRefRegexp matches the metric references of a composite
+// formula, e.g. metric:average("8e5a8b7c-...", "duration")
+var checkCompositeMetricRefRegexp = regexp.MustCompile(`metric:[a-z_]+\(\s*"([^"]+)"\s*,\s*"([^"]+)"\s*\)`)
+
+// schemaCheckComposite takes a single formula: a composite check bundle has
+// exactly one formula and one composite metric in its config (formula and
+// composite_metric_name), the API has no list of formulas.
+var schemaCheckComposite = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckComposite,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkCompositeDescriptions, map[schemaAttr]*schema.Schema{
+			checkCompositeFormulaAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkCompositeFormulaAttr, `\S`),
+			},
+			checkCompositeMetricNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkCompositeMetricNameAttr, `^[\S]+$`),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateComposite reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStateComposite(c *circonusCheck, d *schema.ResourceData) error {
+	compositeConfig := make(map[string]interface{}, len(c.Config))
+
+	compositeConfig[string(checkCompositeFormulaAttr)] = c.Config[config.Formula]
+	compositeConfig[string(checkCompositeMetricNameAttr)] = c.Config[config.CompositeMetricName]
+
+	if err := d.Set(checkCompositeAttr, schema.NewSet(hashCheckComposite, []interface{}{compositeConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkCompositeAttr, err)
+	}
+
+	// the composite metric of each check instance, e.g. for use in graphs
+	metricIDs := make([]string, 0, len(c.Checks))
+	for _, checkCID := range c.Checks {
+		metricIDs = append(metricIDs, checkCompositeMetricID(checkCID, c.Config[config.CompositeMetricName]))
+	}
+
+	if err := d.Set(checkOutCompositeMetricIDsAttr, metricIDs); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkOutCompositeMetricIDsAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckComposite creates a stable hash of the normalized values
+func hashCheckComposite(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkCompositeFormulaAttr)
+	writeString(checkCompositeMetricNameAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIComposite(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeCompositeAttr)
+	if c.Target == "" {
+		c.Target = defaultCheckCompositeTarget
+	}
+
+	// Iterate over all `composite` attributes, even though we have a max of 1
+	// in the schema.
+	for _, mapRaw := range l {
+		compositeConfig := newInterfaceMap(mapRaw)
+
+		if v, found := compositeConfig[checkCompositeFormulaAttr]; found {
+			c.Config[config.Formula] = strings.TrimSpace(v.(string))
+		}
+
+		if v, found := compositeConfig[checkCompositeMetricNameAttr]; found {
+			c.Config[config.CompositeMetricName] = v.(string)
+		}
+	}
+
+	return nil
+}
+
+// checkCompositeMetricRefs returns the check UUID and metric name of each
+// metric referenced in a composite formula.
+func checkCompositeMetricRefs(formula string) [][2]string {
+	matches := checkCompositeMetricRefRegexp.FindAllStringSubmatch(formula, -1)
+
+	refs := make([][2]string, 0, len(matches))
+	for _, m := range matches {
+		refs = append(refs, [2]string{m[1], m[2]})
+	}
+
+	return refs
+}
+
+// checkCompositeMetricID returns the ID of the metric with the passed name
+// collected by the passed check, e.g. /metric/1234_duration
+func checkCompositeMetricID(checkCID, metricName string) string {
+	return fmt.Sprintf("%s/%s_%s", config.MetricPrefix, strings.TrimPrefix(checkCID, config.CheckPrefix+"/"), metricName)
+}
+
+// ValidateComposite verifies that every metric referenced in the formula of a
+// composite check exists.  Metrics of checks using metric filters can not be
+// verified and are skipped.
+func (c *circonusCheck) ValidateComposite(ctxt *providerContext) error {
+	if apiCheckType(c.Type) != apiCheckTypeCompositeAttr {
+		return nil
+	}
+
+	bundles := make(map[string]*api.CheckBundle)
+	for _, ref := range checkCompositeMetricRefs(c.Config[config.Formula]) {
+		uuid, metricName := ref[0], ref[1]
+
+		bundle, found := bundles[uuid]
+		if !found {
+			filter := api.SearchFilterType{"f__check_uuid": []string{uuid}}
+			checks, err := ctxt.client.SearchChecks(nil, &filter)
+			if err != nil {
+				return fmt.Errorf("unable to search for check %q referenced in %s: %w", uuid, checkCompositeFormulaAttr, err)
+			}
+			if checks == nil || len(*checks) == 0 {
+				return fmt.Errorf("check %q referenced in %s not found", uuid, checkCompositeFormulaAttr)
+			}
+
+			bundleCID := (*checks)[0].CheckBundleCID
+			bundle, err = ctxt.client.FetchCheckBundle(api.CIDType(&bundleCID))
+			if err != nil {
+				return fmt.Errorf("unable to fetch check bundle of check %q referenced in %s: %w", uuid, checkCompositeFormulaAttr, err)
+			}
+			bundles[uuid] = bundle
+		}
+
+		if len(bundle.MetricFilters) > 0 {
+			continue
+		}
+
+		metricFound := false
+		for _, m := range bundle.Metrics {
+			if m.Name == metricName {
+				metricFound = true
+				break
+			}
+		}
+		if !metricFound {
+			return fmt.Errorf("metric %q of check %q referenced in %s not found", metricName, uuid, checkCompositeFormulaAttr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package circonus
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckComposite_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: composite check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckCompositeConfigFmt, checkName, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.total_latency", "composite.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.total_latency", "composite.0.metric_name", "total"),
+					resource.TestCheckResourceAttr("circonus_check.total_latency", "composite_metric_ids.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.total_latency", "composite_metric_ids.0", regexp.MustCompile(`^/metric/\d+_total$`)),
+					resource.TestCheckResourceAttr("circonus_check.total_latency", "target", "q._composite"),
+					resource.TestCheckResourceAttr("circonus_check.total_latency", "type", "composite"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusCheckCompositeMissingMetricConfigFmt, checkName),
+				ExpectError: regexp.MustCompile(`must have a metric named "total"`),
+			},
+		},
+	})
+}
+
+func TestCheckCompositeMetricRefs(t *testing.T) {
+	formula := `metric:average("3b5dc5a5-17da-4a2e-8ea3-d28bcd9d4f7e", "maximum") + metric:average("3b5dc5a5-17da-4a2e-8ea3-d28bcd9d4f7e","minimum")`
+
+	expected := [][2]string{
+		{"3b5dc5a5-17da-4a2e-8ea3-d28bcd9d4f7e", "maximum"},
+		{"3b5dc5a5-17da-4a2e-8ea3-d28bcd9d4f7e", "minimum"},
+	}
+	if refs := checkCompositeMetricRefs(formula); !reflect.DeepEqual(refs, expected) {
+		t.Errorf("expected %q, got %q", expected, refs)
+	}
+
+	if refs := checkCompositeMetricRefs("1 + 1"); len(refs) != 0 {
+		t.Errorf("expected no references, got %q", refs)
+	}
+}
+
+const testAccCirconusCheckCompositeConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  metric {
+    name = "minimum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_check" "total_latency" {
+  active = true
+  name = "%s composite"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  composite {
+    formula = "metric:average(\"${circonus_check.api_latency.uuids[0]}\", \"maximum\") + metric:average(\"${circonus_check.api_latency.uuids[0]}\", \"minimum\")"
+    metric_name = "total"
+  }
+
+  metric {
+    name = "total"
+    type = "numeric"
+  }
+}
+`
+
+const testAccCirconusCheckCompositeMissingMetricConfigFmt = `
+resource "circonus_check" "total_latency" {
+  active = true
+  name = "%s composite"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  composite {
+    formula = "1 + 1"
+    metric_name = "total"
+  }
+
+  metric {
+    name = "sum"
+    type = "numeric"
+  }
+}
+`
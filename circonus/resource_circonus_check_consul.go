@@ -27,6 +27,7 @@ const (
 	checkConsulNodeBlacklistAttr        = "node_blacklist"
 	checkConsulServiceAttr              = "service"
 	checkConsulServiceNameBlacklistAttr = "service_blacklist"
+	checkConsulServiceNameWhitelistAttr = "service_whitelist"
 	checkConsulStateAttr                = "state"
 )
 
@@ -45,6 +46,7 @@ var checkConsulDescriptions = attrDescrs{
 	checkConsulNodeBlacklistAttr:        "A blacklist of node names or IDs to exclude from metric results",
 	checkConsulServiceAttr:              "Name of the Consul service to check",
 	checkConsulServiceNameBlacklistAttr: "A blacklist of service names to exclude from metric results",
+	checkConsulServiceNameWhitelistAttr: "A whitelist of service names, only these services are included in the metric results",
 	checkConsulStateAttr:                "Check for Consul services in this particular state",
 }
 
@@ -145,6 +147,14 @@ var schemaCheckConsul = &schema.Schema{
 					ValidateFunc: validateRegexp(checkConsulServiceNameBlacklistAttr, `^[A-Za-z0-9_-]+$`),
 				},
 			},
+			checkConsulServiceNameWhitelistAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRegexp(checkConsulServiceNameWhitelistAttr, `^[A-Za-z0-9_-]+$`),
+				},
+			},
 			checkConsulStateAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -240,15 +250,19 @@ func checkAPIToStateConsul(c *circonusCheck, d *schema.ResourceData) error {
 	}
 
 	if v, found := c.Config[apiConsulCheckBlacklist]; found {
-		consulConfig[checkConsulCheckNameBlacklistAttr] = strings.Split(v, ",")
+		consulConfig[checkConsulCheckNameBlacklistAttr] = checkConsulListFromAPI(v)
 	}
 
 	if v, found := c.Config[apiConsulNodeBlacklist]; found {
-		consulConfig[checkConsulNodeBlacklistAttr] = strings.Split(v, ",")
+		consulConfig[checkConsulNodeBlacklistAttr] = checkConsulListFromAPI(v)
 	}
 
 	if v, found := c.Config[apiConsulServiceBlacklist]; found {
-		consulConfig[checkConsulServiceNameBlacklistAttr] = strings.Split(v, ",")
+		consulConfig[checkConsulServiceNameBlacklistAttr] = checkConsulListFromAPI(v)
+	}
+
+	if v, found := c.Config[apiConsulServiceWhitelist]; found {
+		consulConfig[checkConsulServiceNameWhitelistAttr] = checkConsulListFromAPI(v)
 	}
 
 	// NOTE(sean@): headers attribute processed last.  See below.
@@ -403,7 +417,30 @@ func checkConfigToAPIConsul(c *circonusCheck, l interfaceList) error {
 			}
 			c.Config[apiConsulServiceBlacklist] = strings.Join(checks, ",")
 		}
+
+		if v, found := consulConfig[checkConsulServiceNameWhitelistAttr]; found {
+			listRaw := v.([]interface{})
+			if len(listRaw) > 0 && c.Config[apiConsulServiceBlacklist] != "" {
+				return fmt.Errorf("%s conflicts with %s, use only one of them", checkConsulServiceNameWhitelistAttr, checkConsulServiceNameBlacklistAttr)
+			}
+
+			services := make([]string, 0, len(listRaw))
+			for _, v := range listRaw {
+				services = append(services, v.(string))
+			}
+			c.Config[apiConsulServiceWhitelist] = strings.Join(services, ",")
+		}
 	}
 
 	return nil
 }
+
+// checkConsulListFromAPI returns the entries of a comma separated list of the
+// config in their configured order, an empty list has no entries.
+func checkConsulListFromAPI(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, ",")
+}
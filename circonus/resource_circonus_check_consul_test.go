@@ -9,6 +9,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -165,6 +166,45 @@ func TestAccCirconusCheckConsul_state(t *testing.T) {
 	})
 }
 
+func TestCheckConsulServiceWhitelist(t *testing.T) {
+	consulConfig := map[string]interface{}{
+		string(checkConsulCheckNameBlacklistAttr):   []interface{}{},
+		string(checkConsulHTTPAddrAttr):             "http://consul.service.consul:8501",
+		string(checkConsulServiceAttr):              "web",
+		string(checkConsulServiceNameBlacklistAttr): []interface{}{},
+		string(checkConsulServiceNameWhitelistAttr): []interface{}{"web", "api", "db"},
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIConsul(&c, interfaceList{consulConfig}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := c.Config[apiConsulServiceWhitelist]; v != "web,api,db" {
+		t.Errorf("expected %s %q, got %q", apiConsulServiceWhitelist, "web,api,db", v)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+	if err := checkAPIToStateConsul(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	whitelist := d.Get(checkConsulAttr + ".0." + checkConsulServiceNameWhitelistAttr).([]interface{})
+	if fmt.Sprint(whitelist) != "[web api db]" {
+		t.Errorf("expected the %s in its configured order, got %v", checkConsulServiceNameWhitelistAttr, whitelist)
+	}
+
+	if blacklist := d.Get(checkConsulAttr + ".0." + checkConsulCheckNameBlacklistAttr).([]interface{}); len(blacklist) != 0 {
+		t.Errorf("expected an empty %s, got %q", checkConsulCheckNameBlacklistAttr, blacklist)
+	}
+
+	consulConfig[string(checkConsulServiceNameBlacklistAttr)] = []interface{}{"cache"}
+	c = newCheck()
+	if err := checkConfigToAPIConsul(&c, interfaceList{consulConfig}); err == nil {
+		t.Errorf("expected an error using both %s and %s", checkConsulServiceNameWhitelistAttr, checkConsulServiceNameBlacklistAttr)
+	}
+}
+
 const testAccCirconusCheckConsulConfigV1HealthNodeFmt = `
 resource "circonus_check" "consul_server" {
   active = true
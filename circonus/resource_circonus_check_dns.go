@@ -8,21 +8,27 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	// circonus_check.dns.* resource attribute names
-	checkDNSCTypeAttr      = "ctype"
-	checkDNSNameserverAttr = "nameserver"
-	checkDNSQueryAttr      = "query"
-	checkDNSRTypeAttr      = "rtype"
+	checkDNSCTypeAttr          = "ctype"
+	checkDNSExpectedAnswerAttr = "expected_answer"
+	checkDNSNameserverAttr     = "nameserver"
+	checkDNSQueryAttr          = "query"
+	checkDNSRTypeAttr          = "rtype"
 )
 
+// checkDNSExpectedAnswer is the check bundle config key of the expected answer
+const checkDNSExpectedAnswer = config.Key("expected_answer")
+
 var checkDNSDescriptions = attrDescrs{
-	checkDNSCTypeAttr:      "The DNS class of the query. IN: Internet, CH: Chaos, HS: Hesoid.",
-	checkDNSNameserverAttr: "The domain name server to query. If the name of the check is in-addr.arpa, the system default nameserver is used. Otherwise, the nameserver is the %[target] of the the check.",
-	checkDNSQueryAttr:      "The query to send. If the name of the check is in-addr.arpa, the reverse IP octet notation of in-addr.arpa syntax is synthesized by default. Otherwise the default query is the name of the check itself.",
-	checkDNSRTypeAttr:      "The DNS resource record type of the query. If the name of the check is in-addr.arpa, the default is PTR, otherwise it is A.",
+	checkDNSCTypeAttr:          "The DNS class of the query. IN: Internet, CH: Chaos, HS: Hesoid.",
+	checkDNSExpectedAnswerAttr: "A regular expression the answer must match, the check reports an error otherwise.",
+	checkDNSNameserverAttr:     "The domain name server to query. If the name of the check is in-addr.arpa, the system default nameserver is used. Otherwise, the nameserver is the %[target] of the the check.",
+	checkDNSQueryAttr:          "The query to send. If the name of the check is in-addr.arpa, the reverse IP octet notation of in-addr.arpa syntax is synthesized by default. Otherwise the default query is the name of the check itself.",
+	checkDNSRTypeAttr:          "The DNS resource record type of the query. If the name of the check is in-addr.arpa, the default is PTR, otherwise it is A.",
 }
 
 var schemaCheckDNS = &schema.Schema{
@@ -39,6 +45,11 @@ var schemaCheckDNS = &schema.Schema{
 				Default:      "IN",
 				ValidateFunc: validateStringIn(checkDNSCTypeAttr, validStringValues{"IN", "CH", "HS"}),
 			},
+			checkDNSExpectedAnswerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
 			checkDNSNameserverAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -48,27 +59,13 @@ var schemaCheckDNS = &schema.Schema{
 			checkDNSQueryAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
-				ValidateFunc: validateRegexp(checkDNSNameserverAttr, ".+"),
+				ValidateFunc: validateRegexp(checkDNSQueryAttr, `\S`),
 			},
 			checkDNSRTypeAttr: {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "A",
-				ValidateFunc: validateStringIn(checkDNSCTypeAttr, validStringValues{
-					"A",
-					"AAAA",
-					"TXT",
-					"MX",
-					"SOA",
-					"CNAME",
-					"PTR",
-					"NS",
-					"MB",
-					"MD",
-					"MF",
-					"MG",
-					"MR",
-				}),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "A",
+				ValidateFunc: validateStringIn(checkDNSRTypeAttr, validCheckDNSRTypes),
 			},
 		}),
 	},
@@ -83,6 +80,10 @@ func checkAPIToStateDNS(c *circonusCheck, d *schema.ResourceData) error {
 		dnsConfig[string(checkDNSCTypeAttr)] = ctype
 	}
 
+	if answer, ok := c.Config[checkDNSExpectedAnswer]; ok {
+		dnsConfig[string(checkDNSExpectedAnswerAttr)] = answer
+	}
+
 	if ns, ok := c.Config[config.Nameserver]; ok {
 		dnsConfig[string(checkDNSNameserverAttr)] = ns
 	}
@@ -102,7 +103,7 @@ func checkAPIToStateDNS(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
-// hashCheckICMPPing creates a stable hash of the normalized values
+// hashCheckDNS creates a stable hash of the normalized values
 func hashCheckDNS(v interface{}) int {
 	m := v.(map[string]interface{})
 	b := &bytes.Buffer{}
@@ -115,6 +116,7 @@ func hashCheckDNS(v interface{}) int {
 	}
 
 	writeString(checkDNSCTypeAttr)
+	writeString(checkDNSExpectedAnswerAttr)
 	writeString(checkDNSNameserverAttr)
 	writeString(checkDNSQueryAttr)
 	writeString(checkDNSRTypeAttr)
@@ -133,6 +135,10 @@ func checkConfigToAPIDNS(c *circonusCheck, l interfaceList) error {
 		c.Config[config.CType] = v.(string)
 	}
 
+	if v, found := dnsConfig[checkDNSExpectedAnswerAttr]; found && v.(string) != "" {
+		c.Config[checkDNSExpectedAnswer] = v.(string)
+	}
+
 	if v, found := dnsConfig[checkDNSNameserverAttr]; found && v.(string) != "" {
 		c.Config[config.Nameserver] = v.(string)
 	}
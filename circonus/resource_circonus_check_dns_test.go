@@ -43,6 +43,32 @@ func TestAccCirconusCheckDNS_basic(t *testing.T) {
 	})
 }
 
+func TestAccCirconusCheckDNS_txt(t *testing.T) {
+	checkName := fmt.Sprintf("DNS TXT check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckDNSTXTConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.spf", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.spf", "dns.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.spf", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.spf", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.spf", "type", "dns"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusCheckDNSInvalidRTypeConfigFmt, checkName),
+				ExpectError: regexp.MustCompile(`Invalid "rtype" specified`),
+			},
+		},
+	})
+}
+
 const testAccCirconusCheckDNSConfigFmt = `
 variable "test_tags" {
   type = "list"
@@ -85,3 +111,59 @@ resource "circonus_check" "google" {
   target = "api.circonus.com"
 }
 `
+
+const testAccCirconusCheckDNSTXTConfigFmt = `
+resource "circonus_check" "spf" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  dns {
+    query = "google.com"
+    rtype = "TXT"
+    expected_answer = "v=spf1"
+  }
+
+  metric {
+    name = "answer"
+    type = "text"
+  }
+
+  metric {
+    name = "rtt"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "google.com"
+}
+`
+
+const testAccCirconusCheckDNSInvalidRTypeConfigFmt = `
+resource "circonus_check" "spf" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  dns {
+    query = "google.com"
+    rtype = "SPF"
+  }
+
+  metric {
+    name = "answer"
+    type = "text"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+  target = "google.com"
+}
+`
@@ -12,12 +12,14 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	// circonus_check.http.* resource attribute names
 	checkHTTPAuthMethodAttr   = "auth_method"
 	checkHTTPAuthPasswordAttr = "auth_password"
+	checkHTTPAuthTokenAttr    = "auth_token"
 	checkHTTPAuthUserAttr     = "auth_user"
 	checkHTTPBodyRegexpAttr   = "body_regexp"
 	checkHTTPCAChainAttr      = "ca_chain"
@@ -35,9 +37,17 @@ const (
 	checkHTTPRedirectsAttr    = "redirects"
 )
 
+const (
+	// checkHTTPAuthHeader carries the auth_token, prefixed by
+	// checkHTTPBearerPrefix
+	checkHTTPAuthHeader   = "Authorization"
+	checkHTTPBearerPrefix = "Bearer "
+)
+
 var checkHTTPDescriptions = attrDescrs{
 	checkHTTPAuthMethodAttr:   "The HTTP Authentication method",
 	checkHTTPAuthPasswordAttr: "The HTTP Authentication user password",
+	checkHTTPAuthTokenAttr:    "A bearer token sent in the Authorization header, an alternative to auth_user and auth_password",
 	checkHTTPAuthUserAttr:     "The HTTP Authentication user name",
 	checkHTTPBodyRegexpAttr:   `This regular expression is matched against the body of the response. If a match is not found, the check will be marked as "bad.`,
 	checkHTTPCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
@@ -74,15 +84,24 @@ var schemaCheckHTTP = &schema.Schema{
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkHTTPAuthPasswordAttr, `^.*`),
 			},
+			checkHTTPAuthTokenAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkHTTPAuthTokenAttr, `^\S+$`),
+			},
 			checkHTTPAuthUserAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validateRegexp(checkHTTPAuthUserAttr, `[^:]+`),
 			},
 			checkHTTPBodyRegexpAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validateRegexp(checkHTTPBodyRegexpAttr, `.+`),
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateRegexp(checkHTTPBodyRegexpAttr, `.+`),
+					validation.StringIsValidRegExp,
+				),
 			},
 			checkHTTPCAChainAttr: {
 				Type:         schema.TypeString,
@@ -100,10 +119,13 @@ var schemaCheckHTTP = &schema.Schema{
 				ValidateFunc: validateRegexp(checkHTTPCiphersAttr, `.+`),
 			},
 			checkHTTPCodeRegexpAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      defaultCheckHTTPCodeRegexp,
-				ValidateFunc: validateRegexp(checkHTTPCodeRegexpAttr, `.+`),
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultCheckHTTPCodeRegexp,
+				ValidateFunc: validateFuncs(
+					validateRegexp(checkHTTPCodeRegexpAttr, `.+`),
+					validation.StringIsValidRegExp,
+				),
 			},
 			checkHTTPExtractAttr: {
 				Type:         schema.TypeString,
@@ -125,7 +147,7 @@ var schemaCheckHTTP = &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
 				Default:      defaultCheckHTTPMethod,
-				ValidateFunc: validateRegexp(checkHTTPMethodAttr, `\S+`),
+				ValidateFunc: validateStringIn(checkHTTPMethodAttr, validCheckHTTPMethods),
 			},
 			checkHTTPPayloadAttr: {
 				Type:         schema.TypeString,
@@ -205,6 +227,15 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.Code, checkHTTPCodeRegexpAttr)
 	saveStringConfigToState(config.Extract, checkHTTPExtractAttr)
 
+	// a bearer Authorization header is stored as auth_token unless it was
+	// configured in the headers, header names are case-insensitive
+	authHeaderConfigured := false
+	for k := range checkHTTPConfiguredHeaders(d) {
+		if isCheckHTTPAuthHeader(k) {
+			authHeaderConfigured = true
+		}
+	}
+
 	headers := make(map[string]interface{}, len(c.Config))
 	headerPrefixLen := len(config.HeaderPrefix)
 	for k, v := range c.Config {
@@ -213,8 +244,12 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if strings.Compare(string(k[:headerPrefixLen]), string(config.HeaderPrefix)) == 0 {
-			key := k[headerPrefixLen:]
-			headers[string(key)] = v
+			key := string(k[headerPrefixLen:])
+			if isCheckHTTPAuthHeader(key) && !authHeaderConfigured && strings.HasPrefix(v, checkHTTPBearerPrefix) {
+				httpConfig[string(checkHTTPAuthTokenAttr)] = strings.TrimPrefix(v, checkHTTPBearerPrefix)
+			} else {
+				headers[key] = v
+			}
 		}
 		delete(swamp, k)
 	}
@@ -250,6 +285,29 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
+// checkHTTPConfiguredHeaders returns the headers of the http block in the
+// config, nil when there is none (e.g. on import).
+func checkHTTPConfiguredHeaders(d *schema.ResourceData) map[string]interface{} {
+	s, ok := d.Get(checkHTTPAttr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return nil
+	}
+
+	httpConfig, ok := s.List()[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	headers, _ := httpConfig[string(checkHTTPHeadersAttr)].(map[string]interface{})
+	return headers
+}
+
+// isCheckHTTPAuthHeader returns true if the header name k is the
+// Authorization header, in any case
+func isCheckHTTPAuthHeader(k string) bool {
+	return strings.EqualFold(k, checkHTTPAuthHeader)
+}
+
 // hashCheckHTTP creates a stable hash of the normalized values
 func hashCheckHTTP(v interface{}) int {
 	m := v.(map[string]interface{})
@@ -272,6 +330,7 @@ func hashCheckHTTP(v interface{}) int {
 	// reconciliation with other lists.
 	writeString(checkHTTPAuthMethodAttr)
 	writeString(checkHTTPAuthPasswordAttr)
+	writeString(checkHTTPAuthTokenAttr)
 	writeString(checkHTTPAuthUserAttr)
 	writeString(checkHTTPBodyRegexpAttr)
 	writeString(checkHTTPCAChainAttr)
@@ -363,11 +422,28 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 		c.Config[config.Extract] = v.(string)
 	}
 
-	for k, v := range httpConfig.CollectMap(checkHTTPHeadersAttr) {
+	headers := httpConfig.CollectMap(checkHTTPHeadersAttr)
+	for k, v := range headers {
 		h := config.HeaderPrefix + config.Key(k)
 		c.Config[h] = v
 	}
 
+	if v, found := httpConfig[checkHTTPAuthTokenAttr]; found && v.(string) != "" {
+		for _, attr := range []schemaAttr{checkHTTPAuthMethodAttr, checkHTTPAuthPasswordAttr, checkHTTPAuthUserAttr} {
+			if s, found := httpConfig[string(attr)]; found && s.(string) != "" {
+				return fmt.Errorf("%s conflicts with %s", checkHTTPAuthTokenAttr, attr)
+			}
+		}
+
+		for k := range headers {
+			if isCheckHTTPAuthHeader(k) {
+				return fmt.Errorf("%s conflicts with the %s header in %s", checkHTTPAuthTokenAttr, k, checkHTTPHeadersAttr)
+			}
+		}
+
+		c.Config[config.HeaderPrefix+checkHTTPAuthHeader] = checkHTTPBearerPrefix + v.(string)
+	}
+
 	if v, found := httpConfig[checkHTTPKeyFileAttr]; found {
 		c.Config[config.KeyFile] = v.(string)
 	}
@@ -407,5 +483,14 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 	}
 	// }
 
+	// the TLS options are ignored by the broker for plain http URLs
+	if !strings.HasPrefix(strings.ToLower(c.Config[config.URL]), "https://") {
+		for _, attr := range []schemaAttr{checkHTTPCAChainAttr, checkHTTPCertFileAttr, checkHTTPCiphersAttr, checkHTTPKeyFileAttr} {
+			if v, found := httpConfig[string(attr)]; found && v.(string) != "" {
+				return fmt.Errorf("%s requires an https %s", attr, checkHTTPURLAttr)
+			}
+		}
+	}
+
 	return nil
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckHTTP_basic(t *testing.T) {
@@ -112,6 +114,141 @@ func TestAccCirconusCheckHTTP_basic(t *testing.T) {
 	})
 }
 
+func TestCheckHTTPAuthToken(t *testing.T) {
+	httpConfig := map[string]interface{}{
+		string(checkHTTPAuthTokenAttr): "s3cr3t",
+		string(checkHTTPHeadersAttr):   map[string]interface{}{"Accept": "application/json"},
+		string(checkHTTPMethodAttr):    "POST",
+		string(checkHTTPPayloadAttr):   `{"ping":true}`,
+		string(checkHTTPReadLimitAttr): 0,
+		string(checkHTTPURLAttr):       "https://api.example.com/v1/ping",
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIHTTP(&c, interfaceList{httpConfig}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := c.Config[config.HeaderPrefix+checkHTTPAuthHeader]; v != "Bearer s3cr3t" {
+		t.Errorf("expected the bearer Authorization header, got %q", v)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+	if err := checkAPIToStateHTTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(checkHTTPAttr).(*schema.Set).List()[0].(map[string]interface{})
+	if v := state[string(checkHTTPAuthTokenAttr)]; v != "s3cr3t" {
+		t.Errorf("expected %s %q, got %q", checkHTTPAuthTokenAttr, "s3cr3t", v)
+	}
+	if headers := state[string(checkHTTPHeadersAttr)].(map[string]interface{}); len(headers) != 1 || headers["Accept"] != "application/json" {
+		t.Errorf("expected only the Accept header, got %v", headers)
+	}
+	if hashCheckHTTP(state) != hashCheckHTTP(httpConfig) {
+		t.Errorf("expected the http block to round trip, got %v", state)
+	}
+
+	conflicts := []map[string]interface{}{
+		{string(checkHTTPAuthTokenAttr): "s3cr3t", string(checkHTTPAuthUserAttr): "admin"},
+		{string(checkHTTPAuthTokenAttr): "s3cr3t", string(checkHTTPHeadersAttr): map[string]interface{}{"authorization": "Basic Zm9v"}},
+	}
+	for _, httpConfig := range conflicts {
+		c := newCheck()
+		if err := checkConfigToAPIHTTP(&c, interfaceList{httpConfig}); err == nil {
+			t.Errorf("expected an error for %v", httpConfig)
+		}
+	}
+}
+
+func TestCheckHTTPAuthHeaderCase(t *testing.T) {
+	tests := []struct {
+		apiHeader  string
+		configured map[string]interface{}
+		authToken  string
+	}{
+		// a bearer header is read back as auth_token whatever its case
+		{"authorization", nil, "s3cr3t"},
+		{"AUTHORIZATION", nil, "s3cr3t"},
+		// unless it was configured in the headers, in any case
+		{"Authorization", map[string]interface{}{"authorization": "Bearer s3cr3t"}, ""},
+		{"authorization", map[string]interface{}{"Authorization": "Bearer s3cr3t"}, ""},
+	}
+
+	for _, test := range tests {
+		raw := map[string]interface{}{}
+		if test.configured != nil {
+			raw[checkHTTPAttr] = []interface{}{map[string]interface{}{
+				string(checkHTTPHeadersAttr): test.configured,
+				string(checkHTTPURLAttr):     "https://api.example.com/v1/ping",
+			}}
+		}
+		d := schema.TestResourceDataRaw(t, resourceCheck().Schema, raw)
+
+		c := newCheck()
+		c.Config[config.URL] = "https://api.example.com/v1/ping"
+		c.Config[config.HeaderPrefix+config.Key(test.apiHeader)] = "Bearer s3cr3t"
+		if err := checkAPIToStateHTTP(&c, d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		state := d.Get(checkHTTPAttr).(*schema.Set).List()[0].(map[string]interface{})
+		headers := state[string(checkHTTPHeadersAttr)].(map[string]interface{})
+		if v := state[string(checkHTTPAuthTokenAttr)]; v != test.authToken {
+			t.Errorf("%s %v: expected %s %q, got %q", test.apiHeader, test.configured, checkHTTPAuthTokenAttr, test.authToken, v)
+		}
+		if _, found := headers[test.apiHeader]; found == (test.authToken != "") {
+			t.Errorf("%s %v: unexpected headers %v", test.apiHeader, test.configured, headers)
+		}
+	}
+}
+
+func TestCheckHTTPAssertionRegexps(t *testing.T) {
+	httpSchema := schemaCheckHTTP.Elem.(*schema.Resource).Schema
+
+	tests := []struct {
+		attr  schemaAttr
+		value string
+		valid bool
+	}{
+		{checkHTTPCodeRegexpAttr, `^2\d\d$`, true},
+		{checkHTTPCodeRegexpAttr, `^(200|204$`, false},
+		{checkHTTPBodyRegexpAttr, `"status":\s*"ok"`, true},
+		{checkHTTPBodyRegexpAttr, `[ok`, false},
+	}
+
+	for _, test := range tests {
+		_, errs := httpSchema[string(test.attr)].ValidateFunc(test.value, string(test.attr))
+		if valid := len(errs) == 0; valid != test.valid {
+			t.Errorf("%s %q: expected valid %t, got %v", test.attr, test.value, test.valid, errs)
+		}
+	}
+}
+
+func TestCheckHTTPTLSOptions(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://www.example.com/healthz", true},
+		{"HTTPS://www.example.com/healthz", true},
+		{"http://www.example.com/healthz", false},
+	}
+
+	for _, test := range tests {
+		httpConfig := map[string]interface{}{
+			string(checkHTTPCAChainAttr): "/etc/ssl/ca.pem",
+			string(checkHTTPURLAttr):     test.url,
+		}
+
+		c := newCheck()
+		err := checkConfigToAPIHTTP(&c, interfaceList{httpConfig})
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%s with %s: expected valid %t, got %v", checkHTTPCAChainAttr, test.url, test.valid, err)
+		}
+	}
+}
+
 const testAccCirconusCheckHTTPConfigFmt = `
 variable "http_check_tags" {
   type = "list"
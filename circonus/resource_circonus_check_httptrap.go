@@ -14,11 +14,13 @@ import (
 const (
 	// circonus_check.httptrap.* resource attribute names
 	checkHTTPTrapAsyncMetricsAttr = "async_metrics"
+	checkHTTPTrapExtractionAttr   = "extraction"
 	checkHTTPTrapSecretAttr       = "secret"
 )
 
 var checkHTTPTrapDescriptions = attrDescrs{
 	checkHTTPTrapAsyncMetricsAttr: "Specify whether httptrap metrics are logged immediately or held until the status message is emitted",
+	checkHTTPTrapExtractionAttr:   "Extract the values at the given paths of the submitted JSON document as named metrics",
 	checkHTTPTrapSecretAttr:       "",
 }
 
@@ -35,6 +37,7 @@ var schemaCheckHTTPTrap = &schema.Schema{
 				Optional: true,
 				Default:  defaultCheckHTTPTrapAsync,
 			},
+			checkHTTPTrapExtractionAttr: schemaCheckJSONExtraction,
 			checkHTTPTrapSecretAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -80,6 +83,7 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 	}
 
 	saveBoolConfigToState(config.AsyncMetrics, checkHTTPTrapAsyncMetricsAttr)
+	httpTrapConfig[string(checkHTTPTrapExtractionAttr)] = checkJSONExtractionAPIToState(c, swamp)
 	saveStringConfigToState(config.Secret, checkHTTPTrapSecretAttr)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
@@ -125,6 +129,7 @@ func hashCheckHTTPTrap(v interface{}) int {
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
 	writeBool(checkHTTPTrapAsyncMetricsAttr)
+	writeCheckJSONExtraction(b, m[string(checkHTTPTrapExtractionAttr)])
 	writeString(checkHTTPTrapSecretAttr)
 
 	s := b.String()
@@ -146,6 +151,10 @@ func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error {
 			}
 		}
 
+		if err := checkJSONExtractionConfigToAPI(c, httpTrapConfig[checkHTTPTrapExtractionAttr]); err != nil {
+			return err
+		}
+
 		if v, found := httpTrapConfig[checkHTTPTrapSecretAttr]; found {
 			c.Config[config.Secret] = v.(string)
 		}
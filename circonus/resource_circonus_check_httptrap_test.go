@@ -36,6 +36,7 @@ func TestAccCirconusCheckHTTPTrap_basic(t *testing.T) {
 
 					resource.TestCheckResourceAttr("circonus_check.consul", "metric.1.active", "true"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "metric.1.name", "consul`consul-server-10-151-2-8`runtime`alloc_bytes"),
+					resource.TestCheckResourceAttr("circonus_check.consul", "metric.1.tags.#", "2"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "metric.1.type", "numeric"),
 
 					resource.TestCheckResourceAttr("circonus_check.consul", "metric.2.active", "true"),
@@ -87,6 +88,7 @@ resource "circonus_check" "consul" {
 
   metric {
     name = "consul` + "`" + `${var.consul_hostname}` + "`" + `runtime` + "`" + `alloc_bytes"
+    tags = [ "unit:bytes", "source:consul", "unit:bytes" ]
     type = "numeric"
   }
 
@@ -16,12 +16,14 @@ const (
 	checkICMPPingAvailabilityAttr = "availability"
 	checkICMPPingCountAttr        = "count"
 	checkICMPPingIntervalAttr     = "interval"
+	checkICMPPingPacketSizeAttr   = "packet_size"
 )
 
 var checkICMPPingDescriptions = attrDescrs{
 	checkICMPPingAvailabilityAttr: `The percentage of ICMP available required for the check to be considered "good."`,
 	checkICMPPingCountAttr:        "The number of ICMP requests to send during a single check.",
 	checkICMPPingIntervalAttr:     "The number of milliseconds between ICMP requests.",
+	checkICMPPingPacketSizeAttr:   "The size in bytes of the payload of each ICMP request.",
 }
 
 var schemaCheckICMPPing = &schema.Schema{
@@ -46,7 +48,7 @@ var schemaCheckICMPPing = &schema.Schema{
 				Optional: true,
 				Default:  defaultCheckICMPPingCount,
 				ValidateFunc: validateFuncs(
-					validateIntMin(checkICMPPingCountAttr, 0),
+					validateIntMin(checkICMPPingCountAttr, 1),
 					validateIntMax(checkICMPPingCountAttr, 20),
 				),
 			},
@@ -59,6 +61,15 @@ var schemaCheckICMPPing = &schema.Schema{
 					validateDurationMax(checkICMPPingIntervalAttr, "5m"),
 				),
 			},
+			checkICMPPingPacketSizeAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  defaultCheckICMPPingPacketSize,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkICMPPingPacketSizeAttr, 0),
+					validateIntMax(checkICMPPingPacketSizeAttr, maxCheckICMPPingPacketSize),
+				),
+			},
 		}),
 	},
 }
@@ -68,24 +79,49 @@ var schemaCheckICMPPing = &schema.Schema{
 func checkAPIToStateICMPPing(c *circonusCheck, d *schema.ResourceData) error {
 	icmpPingConfig := make(map[string]interface{}, len(c.Config))
 
-	availNeeded, err := strconv.ParseFloat(c.Config[config.AvailNeeded], 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.AvailNeeded, err)
+	// Settings the API does not return are stored with the provider's
+	// defaults, which are also what the collector uses, so they don't show up
+	// as a diff.
+	availNeeded := defaultCheckICMPPingAvailability
+	if s, ok := c.Config[config.AvailNeeded]; ok && s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.AvailNeeded, err)
+		}
+		availNeeded = f
 	}
 
-	count, err := strconv.ParseInt(c.Config[config.Count], 10, 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Count, err)
+	count := int64(defaultCheckICMPPingCount)
+	if s, ok := c.Config[config.Count]; ok && s != "" {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Count, err)
+		}
+		count = i
 	}
 
-	interval, err := time.ParseDuration(fmt.Sprintf("%sms", c.Config[config.Interval]))
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Interval, err)
+	interval, _ := time.ParseDuration(defaultCheckICMPPingInterval)
+	if s, ok := c.Config[config.Interval]; ok && s != "" {
+		d, err := time.ParseDuration(fmt.Sprintf("%sms", s))
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Interval, err)
+		}
+		interval = d
+	}
+
+	packetSize := int64(defaultCheckICMPPingPacketSize)
+	if s, ok := c.Config[config.Size]; ok && s != "" {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Size, err)
+		}
+		packetSize = i
 	}
 
 	icmpPingConfig[string(checkICMPPingAvailabilityAttr)] = availNeeded
 	icmpPingConfig[string(checkICMPPingCountAttr)] = int(count)
 	icmpPingConfig[string(checkICMPPingIntervalAttr)] = interval.String()
+	icmpPingConfig[string(checkICMPPingPacketSizeAttr)] = int(packetSize)
 
 	if err := d.Set(checkICMPPingAttr, schema.NewSet(hashCheckICMPPing, []interface{}{icmpPingConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkICMPPingAttr, err)
@@ -124,6 +160,7 @@ func hashCheckICMPPing(v interface{}) int {
 	writeFloat64(checkICMPPingAvailabilityAttr)
 	writeInt(checkICMPPingCountAttr)
 	writeDuration(checkICMPPingIntervalAttr)
+	writeInt(checkICMPPingPacketSizeAttr)
 
 	s := b.String()
 	return hashcode.String(s)
@@ -138,8 +175,7 @@ func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error {
 		icmpPingConfig := newInterfaceMap(mapRaw)
 
 		if v, found := icmpPingConfig[checkICMPPingAvailabilityAttr]; found {
-			f := v.(float64)
-			c.Config[config.AvailNeeded] = fmt.Sprintf("%d", int(f))
+			c.Config[config.AvailNeeded] = strconv.FormatFloat(v.(float64), 'f', -1, 64)
 		}
 
 		if v, found := icmpPingConfig[checkICMPPingCountAttr]; found {
@@ -150,6 +186,10 @@ func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error {
 			d, _ := time.ParseDuration(v.(string))
 			c.Config[config.Interval] = fmt.Sprintf("%d", int64(d/time.Millisecond))
 		}
+
+		if v, found := icmpPingConfig[checkICMPPingPacketSizeAttr]; found {
+			c.Config[config.Size] = fmt.Sprintf("%d", v.(int))
+		}
 	}
 
 	return nil
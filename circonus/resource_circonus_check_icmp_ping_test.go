@@ -34,6 +34,7 @@ func TestAccCirconusCheckICMPPing_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "icmp_ping.0.availability", "100"),
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "icmp_ping.0.count", "5"),
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "icmp_ping.0.interval", "500ms"),
+					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "icmp_ping.0.packet_size", "1024"),
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "name", checkName),
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "period", "300s"),
 					resource.TestCheckResourceAttr("circonus_check.loopback_latency", "metric.#", "5"),
@@ -86,6 +87,7 @@ resource "circonus_check" "loopback_latency" {
     availability = "100.0"
     count = 5
     interval = "500ms"
+    packet_size = 1024
   }
 
   metric {
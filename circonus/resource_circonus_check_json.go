@@ -22,6 +22,7 @@ const (
 	checkJSONCAChainAttr      = "ca_chain"
 	checkJSONCertFileAttr     = "certificate_file"
 	checkJSONCiphersAttr      = "ciphers"
+	checkJSONExtractionAttr   = "extraction"
 	checkJSONHeadersAttr      = "headers"
 	checkJSONKeyFileAttr      = "key_file"
 	checkJSONMethodAttr       = "method"
@@ -30,8 +31,18 @@ const (
 	checkJSONReadLimitAttr    = "read_limit"
 	checkJSONURLAttr          = "url"
 	checkJSONVersionAttr      = "version"
+
+	// circonus_check.json.extraction.* and
+	// circonus_check.httptrap.extraction.* resource attribute names
+	checkJSONExtractionMetricNameAttr = "metric_name"
+	checkJSONExtractionPathAttr       = "path"
 )
 
+// checkJSONExtractionPrefix is the prefix of the check bundle config keys the
+// extraction rules compile into, one key per metric, e.g.
+// extract_queue_depth = servers`0`queue depth
+const checkJSONExtractionPrefix = config.Key("extract_")
+
 var checkJSONDescriptions = attrDescrs{
 	checkJSONAuthMethodAttr:   "The HTTP Authentication method",
 	checkJSONAuthPasswordAttr: "The HTTP Authentication user password",
@@ -39,6 +50,7 @@ var checkJSONDescriptions = attrDescrs{
 	checkJSONCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
 	checkJSONCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS-enabled checks)",
 	checkJSONCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for HTTPS checks)",
+	checkJSONExtractionAttr:   "Extract the values at the given paths of the JSON document as named metrics",
 	checkJSONHeadersAttr:      "Map of HTTP Headers to send along with HTTP Requests",
 	checkJSONKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks)",
 	checkJSONMethodAttr:       "The HTTP method to use",
@@ -49,6 +61,31 @@ var checkJSONDescriptions = attrDescrs{
 	checkJSONVersionAttr:      "Sets the HTTP version for the check to use",
 }
 
+var checkJSONExtractionDescriptions = attrDescrs{
+	checkJSONExtractionMetricNameAttr: "The name of the metric produced by this extraction rule",
+	checkJSONExtractionPathAttr:       "The path of the value in the JSON document, its keys and array indexes joined by backticks, e.g. servers`0`queue depth",
+}
+
+var schemaCheckJSONExtraction = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	Set:      hashCheckJSONExtraction,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkJSONExtractionDescriptions, map[schemaAttr]*schema.Schema{
+			checkJSONExtractionMetricNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkJSONExtractionMetricNameAttr, `^\S+$`),
+			},
+			checkJSONExtractionPathAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateJSONPath(checkJSONExtractionPathAttr),
+			},
+		}),
+	},
+}
+
 var schemaCheckJSON = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -88,6 +125,7 @@ var schemaCheckJSON = &schema.Schema{
 				Optional:     true,
 				ValidateFunc: validateRegexp(checkJSONCiphersAttr, `.+`),
 			},
+			checkJSONExtractionAttr: schemaCheckJSONExtraction,
 			checkJSONHeadersAttr: {
 				Type:         schema.TypeMap,
 				Elem:         schema.TypeString,
@@ -181,6 +219,7 @@ func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.CAChain, checkJSONCAChainAttr)
 	saveStringConfigToState(config.CertFile, checkJSONCertFileAttr)
 	saveStringConfigToState(config.Ciphers, checkJSONCiphersAttr)
+	jsonConfig[string(checkJSONExtractionAttr)] = checkJSONExtractionAPIToState(c, swamp)
 
 	headers := make(map[string]interface{}, len(c.Config))
 	headerPrefixLen := len(config.HeaderPrefix)
@@ -254,6 +293,7 @@ func checkJSONConfigChecksum(v interface{}) int {
 	writeString(checkJSONCAChainAttr)
 	writeString(checkJSONCertFileAttr)
 	writeString(checkJSONCiphersAttr)
+	writeCheckJSONExtraction(b, m[string(checkJSONExtractionAttr)])
 
 	if headersRaw, ok := m[string(checkJSONHeadersAttr)]; ok {
 		headerMap := headersRaw.(map[string]interface{})
@@ -313,6 +353,10 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error {
 			c.Config[config.Ciphers] = v.(string)
 		}
 
+		if err := checkJSONExtractionConfigToAPI(c, jsonConfig[checkJSONExtractionAttr]); err != nil {
+			return err
+		}
+
 		for k, v := range jsonConfig.CollectMap(checkJSONHeadersAttr) {
 			h := config.HeaderPrefix + config.Key(k)
 			c.Config[h] = v
@@ -365,3 +409,114 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error {
 
 	return nil
 }
+
+// checkJSONExtractionConfigToAPI compiles the `extraction` rules of a json or
+// httptrap check into the check bundle config.
+func checkJSONExtractionConfigToAPI(c *circonusCheck, extractionRaw interface{}) error {
+	extractionSet, ok := extractionRaw.(*schema.Set)
+	if !ok {
+		return nil
+	}
+
+	for _, ruleRaw := range extractionSet.List() {
+		rule := newInterfaceMap(ruleRaw)
+		name := rule[checkJSONExtractionMetricNameAttr].(string)
+		path := rule[checkJSONExtractionPathAttr].(string)
+
+		if _, err := parseJSONPath(path); err != nil {
+			return fmt.Errorf("invalid %s %s %q for metric %q: %w", checkJSONExtractionAttr, checkJSONExtractionPathAttr, path, name, err)
+		}
+
+		k := checkJSONExtractionPrefix + config.Key(name)
+		if _, found := c.Config[k]; found {
+			return fmt.Errorf("duplicate %s %s %q", checkJSONExtractionAttr, checkJSONExtractionMetricNameAttr, name)
+		}
+		c.Config[k] = path
+	}
+
+	return nil
+}
+
+// checkJSONExtractionAPIToState collects the extraction rules out of the check
+// bundle config, removing their keys from swamp.
+func checkJSONExtractionAPIToState(c *circonusCheck, swamp map[config.Key]string) *schema.Set {
+	rules := make([]interface{}, 0)
+	for k, v := range c.Config {
+		if !strings.HasPrefix(string(k), string(checkJSONExtractionPrefix)) {
+			continue
+		}
+
+		rules = append(rules, map[string]interface{}{
+			string(checkJSONExtractionMetricNameAttr): strings.TrimPrefix(string(k), string(checkJSONExtractionPrefix)),
+			string(checkJSONExtractionPathAttr):       v,
+		})
+		delete(swamp, k)
+	}
+
+	return schema.NewSet(hashCheckJSONExtraction, rules)
+}
+
+// hashCheckJSONExtraction creates a stable hash of a single extraction rule
+func hashCheckJSONExtraction(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	for _, attrName := range []schemaAttr{checkJSONExtractionMetricNameAttr, checkJSONExtractionPathAttr} {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+// writeCheckJSONExtraction writes the extraction rules to the buffer of the hash
+// of their parent block, sorted so the order of the rules does not matter.
+func writeCheckJSONExtraction(b *bytes.Buffer, extractionRaw interface{}) {
+	var rules []interface{}
+	switch v := extractionRaw.(type) {
+	case *schema.Set:
+		rules = v.List()
+	case []interface{}:
+		rules = v
+	default:
+		return
+	}
+
+	hashes := make([]int, 0, len(rules))
+	for _, ruleRaw := range rules {
+		hashes = append(hashes, hashCheckJSONExtraction(ruleRaw))
+	}
+
+	sort.Ints(hashes)
+	for _, h := range hashes {
+		fmt.Fprintf(b, "%x", h)
+	}
+}
+
+// checkJSONPathSeparator separates the object keys and array indexes of a
+// path.  The broker names the values of a JSON document by joining the keys
+// and indexes leading to them with it, e.g. servers`0`queue depth.
+const checkJSONPathSeparator = "`"
+
+// parseJSONPath splits a path in the broker's metric name syntax into its
+// object keys and array indexes, e.g. servers`0`queue depth.  Every key or
+// index must be non-empty.
+func parseJSONPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is empty")
+	}
+
+	parts := strings.Split(path, checkJSONPathSeparator)
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("empty key at position %d, keys and array indexes are separated by a single %s", i, checkJSONPathSeparator)
+		}
+	}
+
+	return parts, nil
+}
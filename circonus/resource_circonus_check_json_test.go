@@ -2,10 +2,12 @@ package circonus
 
 import (
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckJSON_basic(t *testing.T) {
@@ -58,6 +60,7 @@ func TestAccCirconusCheckJSON_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.headers.X-Circonus-App-Name", "TerraformCheck"),
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.headers.X-Circonus-Auth-Token", "<env 'CIRCONUS_API_TOKEN'>"),
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.version", "1.1"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.extraction.#", "2"),
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.method", "GET"),
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.port", "443"),
 					resource.TestCheckResourceAttr("circonus_check.usage", "json.0.read_limit", "1048576"),
@@ -81,6 +84,109 @@ func TestAccCirconusCheckJSON_basic(t *testing.T) {
 	})
 }
 
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path       string
+		parts      []string
+		shouldFail bool
+	}{
+		{"foo", []string{"foo"}, false},
+		{"foo`bar_baz", []string{"foo", "bar_baz"}, false},
+		{"servers`0`queue depth", []string{"servers", "0", "queue depth"}, false},
+		{"_usage`0`_limit", []string{"_usage", "0", "_limit"}, false},
+		{"a.b`12", []string{"a.b", "12"}, false},
+		{"", nil, true},
+		{"`", nil, true},
+		{"`foo", nil, true},
+		{"foo`", nil, true},
+		{"foo``bar", nil, true},
+	}
+
+	for _, test := range tests {
+		parts, err := parseJSONPath(test.path)
+		if test.shouldFail {
+			if err == nil {
+				t.Errorf("expected %q to fail, got %q", test.path, parts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expected %q to pass: %v", test.path, err)
+			continue
+		}
+		if strings.Join(parts, "|") != strings.Join(test.parts, "|") {
+			t.Errorf("expected %q to parse as %q, got %q", test.path, test.parts, parts)
+		}
+	}
+}
+
+func TestCheckJSONExtractionRoundTrip(t *testing.T) {
+	limit := map[string]interface{}{
+		string(checkJSONExtractionMetricNameAttr): "metric_limit",
+		string(checkJSONExtractionPathAttr):       "_usage`0`_limit",
+	}
+	used := map[string]interface{}{
+		string(checkJSONExtractionMetricNameAttr): "metric_used",
+		string(checkJSONExtractionPathAttr):       "_usage`0`_used",
+	}
+
+	checkConfig := func(rules ...interface{}) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+			checkCollectorAttr:    []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkMetricFilterAttr: []interface{}{map[string]interface{}{"type": "allow", "regex": ".+"}},
+			checkJSONAttr: []interface{}{map[string]interface{}{
+				string(checkJSONURLAttr):        "https://api.circonus.com/account/current",
+				string(checkJSONExtractionAttr): rules,
+			}},
+		})
+	}
+
+	d := checkConfig(limit, used)
+	c := newCheck()
+	if err := c.ParseConfig(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		checkJSONExtractionPrefix + "metric_limit": "_usage`0`_limit",
+		checkJSONExtractionPrefix + "metric_used":  "_usage`0`_used",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("expected config %s = %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	// the rules read back from the check bundle match the configured rules,
+	// in whatever order they were configured
+	state := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+	if err := checkAPIToStateJSON(&c, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	extractions := func(d *schema.ResourceData) *schema.Set {
+		return d.Get(checkJSONAttr).(*schema.Set).List()[0].(map[string]interface{})[string(checkJSONExtractionAttr)].(*schema.Set)
+	}
+	for _, configured := range []*schema.ResourceData{d, checkConfig(used, limit)} {
+		if !extractions(state).Equal(extractions(configured)) {
+			t.Errorf("expected %v, got %v", extractions(configured).List(), extractions(state).List())
+		}
+		if !state.Get(checkJSONAttr).(*schema.Set).Equal(configured.Get(checkJSONAttr)) {
+			t.Errorf("expected the %s block read back to match the configured one", checkJSONAttr)
+		}
+	}
+
+	// duplicate metric names can not be compiled into the check bundle
+	dup := map[string]interface{}{
+		string(checkJSONExtractionMetricNameAttr): "metric_limit",
+		string(checkJSONExtractionPathAttr):       "_usage`1`_limit",
+	}
+	c = newCheck()
+	if err := c.ParseConfig(checkConfig(limit, dup)); err == nil {
+		t.Error("expected duplicate metric names to fail")
+	}
+}
+
 const testAccCirconusCheckJSONConfig1 = `
 
 resource "circonus_metric" "limit" {
@@ -162,6 +268,16 @@ resource "circonus_check" "usage" {
     method = "GET"
     port = 443
     read_limit = 1048576
+
+    extraction {
+      metric_name = "metric_limit"
+      path = "_usage` + "`0`" + `_limit"
+    }
+
+    extraction {
+      metric_name = "metric_used"
+      path = "_usage` + "`0`" + `_used"
+    }
   }
 
   metric {
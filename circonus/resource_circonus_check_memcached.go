@@ -30,7 +30,7 @@ var schemaCheckMemcached = &schema.Schema{
 			checkMemcachedPortAttr: {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  11211,
+				Default:  defaultCheckMemcachedPort,
 				ValidateFunc: validateFuncs(
 					validateIntMin(checkMemcachedPortAttr, 1),
 					validateIntMax(checkMemcachedPortAttr, 65535),
@@ -45,9 +45,14 @@ var schemaCheckMemcached = &schema.Schema{
 func checkAPIToStateMemcached(c *circonusCheck, d *schema.ResourceData) error {
 	memcachedConfig := make(map[string]interface{}, len(c.Config))
 
-	port, err := strconv.ParseInt(c.Config[config.Port], 10, 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Port, err)
+	// the broker uses the default port when the config has none
+	port := int64(defaultCheckMemcachedPort)
+	if v, found := c.Config[config.Port]; found && v != "" {
+		p, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Port, err)
+		}
+		port = p
 	}
 
 	memcachedConfig[string(checkMemcachedPortAttr)] = int(port)
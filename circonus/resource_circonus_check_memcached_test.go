@@ -0,0 +1,65 @@
+package circonus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckMemcached_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: Memcached check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckMemcachedConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.memcached", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "memcached.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.memcached", "memcached.*", map[string]string{
+						"port": "11212",
+					}),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "notes", "Check to grab memcached metrics"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "period", "60s"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "target", "127.0.0.1"),
+					resource.TestCheckResourceAttr("circonus_check.memcached", "type", "memcached"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckMemcachedConfigFmt = `
+resource "circonus_check" "memcached" {
+  active = true
+  name = "%s"
+  notes = "Check to grab memcached metrics"
+  period = "60s"
+  target = "127.0.0.1"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  memcached {
+    port = 11212
+  }
+
+  metric_filter {
+    type    = "allow"
+    regex   = ".*"
+    comment = "Allow all metrics"
+  }
+
+  tags = [ "app:memcached", "lifecycle:unittest" ]
+}
+`
@@ -0,0 +1,257 @@
+package circonus
+
+// The `circonus_check_metrics` resource manages the active metrics of a check
+// bundle owned by a `circonus_check` resource with `external_metrics` set.
+// Every metric of the bundle not in the resource's `metric` blocks is
+// deactivated, the other fields of the bundle are left untouched.
+
+import (
+	"fmt"
+	"sort"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check_metrics.* resource attribute names, the attributes of
+	// each metric are shared with the circonus_check_metrics data source
+	checkMetricsMetricAttr = "metric"
+)
+
+var checkMetricsResourceDescriptions = attrDescrs{
+	checkMetricsCheckAttr:  "The CID of the check bundle whose active metrics are managed",
+	checkMetricsMetricAttr: "The active metrics of the check bundle, all other metrics are deactivated",
+}
+
+var checkMetricsMetricDescriptions = attrDescrs{
+	checkMetricsNameAttr:  metricDescriptions[metricNameAttr],
+	checkMetricsTagsAttr:  metricDescriptions[metricTagsAttr],
+	checkMetricsTypeAttr:  metricDescriptions[metricTypeAttr],
+	checkMetricsUnitsAttr: metricDescriptions[metricUnitsAttr],
+}
+
+func resourceCheckMetrics() *schema.Resource {
+	return &schema.Resource{
+		Create: checkMetricsCreate,
+		Read:   checkMetricsRead,
+		Update: checkMetricsUpdate,
+		Delete: checkMetricsDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthroughUnescape,
+		},
+
+		Schema: convertToHelperSchema(checkMetricsResourceDescriptions, map[schemaAttr]*schema.Schema{
+			checkMetricsCheckAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRegexp(checkMetricsCheckAttr, config.CheckBundleCIDRegex),
+			},
+			checkMetricsMetricAttr: {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkMetricsMetricDescriptions, map[schemaAttr]*schema.Schema{
+						checkMetricsNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkMetricsNameAttr, `[\S]+`),
+						},
+						checkMetricsTagsAttr: tagMakeConfigSchema(checkMetricsTagsAttr),
+						checkMetricsTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateMetricType,
+						},
+						checkMetricsUnitsAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkMetricsUnitsAttr, metricUnitsRegexp),
+						},
+					}),
+				},
+			},
+		}),
+	}
+}
+
+func checkMetricsCreate(d *schema.ResourceData, meta interface{}) error {
+	cid := d.Get(checkMetricsCheckAttr).(string)
+	if err := checkMetricsApply(meta.(*providerContext), cid, d); err != nil {
+		return fmt.Errorf("error creating check metrics of %q: %w", cid, err)
+	}
+
+	d.SetId(cid)
+
+	return checkMetricsRead(d, meta)
+}
+
+// checkMetricsRead stores the active metrics of the check bundle into the
+// statefile.
+func checkMetricsRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&cid))
+	if err != nil {
+		if api.IsNotFound(err) {
+			// the check bundle was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	metrics := make([]interface{}, 0, len(cb.Metrics))
+	for _, m := range cb.Metrics {
+		if m.Status != metricStatusActive {
+			continue
+		}
+
+		metricAttrs := map[string]interface{}{
+			string(checkMetricsNameAttr): m.Name,
+			string(checkMetricsTagsAttr): tagsToState(apiToTags(m.Tags)),
+			string(checkMetricsTypeAttr): m.Type,
+		}
+
+		if m.Units != nil {
+			metricAttrs[string(checkMetricsUnitsAttr)] = *m.Units
+		}
+
+		metrics = append(metrics, metricAttrs)
+	}
+
+	d.SetId(cb.CID)
+
+	_ = d.Set(checkMetricsCheckAttr, cb.CID)
+
+	if err := d.Set(checkMetricsMetricAttr, metrics); err != nil {
+		return fmt.Errorf("Unable to store check metrics %q attribute: %w", checkMetricsMetricAttr, err)
+	}
+
+	return nil
+}
+
+func checkMetricsUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := checkMetricsApply(meta.(*providerContext), d.Id(), d); err != nil {
+		return fmt.Errorf("unable to update check metrics of %q: %w", d.Id(), err)
+	}
+
+	return checkMetricsRead(d, meta)
+}
+
+// checkMetricsDelete deactivates the metrics of the check bundle, the check
+// bundle itself is owned by the circonus_check resource.
+func checkMetricsDelete(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&cid))
+	if err != nil {
+		if api.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("unable to delete check metrics of %q: %w", d.Id(), err)
+	}
+
+	cb.Metrics = checkMetricsMerge(cb.Metrics, nil)
+	if _, err := ctxt.client.UpdateCheckBundle(cb); err != nil {
+		return fmt.Errorf("unable to delete check metrics of %q: %w", d.Id(), err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// checkMetricsApply updates the metrics of the check bundle with passed cid
+// to the configured metrics.
+func checkMetricsApply(ctxt *providerContext, cid string, d *schema.ResourceData) error {
+	cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&cid))
+	if err != nil {
+		return err
+	}
+
+	// metric filters activate the metrics they allow, the activations of this
+	// resource would be overwritten
+	if len(cb.MetricFilters) > 0 {
+		return fmt.Errorf("check bundle %s uses metric filters, its metrics can not be managed by circonus_check_metrics", cb.CID)
+	}
+
+	configured, err := checkMetricsFromConfig(d)
+	if err != nil {
+		return err
+	}
+
+	cb.Metrics = checkMetricsMerge(cb.Metrics, configured)
+	if _, err := ctxt.client.UpdateCheckBundle(cb); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkMetricsFromConfig returns the configured metrics, all active.
+func checkMetricsFromConfig(d *schema.ResourceData) ([]api.CheckBundleMetric, error) {
+	metricList := d.Get(checkMetricsMetricAttr).(*schema.Set).List()
+	metrics := make([]api.CheckBundleMetric, 0, len(metricList))
+	names := make(map[string]struct{}, len(metricList))
+	for _, metricRaw := range metricList {
+		m := newMetric()
+		if err := m.ParseConfigMap("", metricRaw.(map[string]interface{})); err != nil {
+			return nil, fmt.Errorf("unable to parse config: %w", err)
+		}
+
+		if _, dup := names[m.Name]; dup {
+			return nil, fmt.Errorf("metric %q is configured more than once", m.Name)
+		}
+		names[m.Name] = struct{}{}
+
+		m.Status = metricStatusActive
+		if m.Tags == nil {
+			m.Tags = []string{}
+		}
+
+		metrics = append(metrics, m.CheckBundleMetric)
+	}
+
+	return metrics, nil
+}
+
+// checkMetricsMerge returns the metrics of a check bundle with the configured
+// metrics active and every other metric available (deactivated).  Configured
+// metrics missing from the bundle are appended, sorted by name.
+func checkMetricsMerge(current, configured []api.CheckBundleMetric) []api.CheckBundleMetric {
+	byName := make(map[string]api.CheckBundleMetric, len(configured))
+	for _, m := range configured {
+		byName[m.Name] = m
+	}
+
+	merged := make([]api.CheckBundleMetric, 0, len(current)+len(configured))
+	for _, m := range current {
+		if cm, ok := byName[m.Name]; ok {
+			m.Status = metricStatusActive
+			m.Tags = cm.Tags
+			m.Type = cm.Type
+			m.Units = cm.Units
+			delete(byName, m.Name)
+		} else {
+			m.Status = metricStatusAvailable
+		}
+		merged = append(merged, m)
+	}
+
+	added := make([]api.CheckBundleMetric, 0, len(byName))
+	for _, m := range byName {
+		added = append(added, m)
+	}
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Name < added[j].Name
+	})
+
+	return append(merged, added...)
+}
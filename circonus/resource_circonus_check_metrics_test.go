@@ -0,0 +1,125 @@
+package circonus
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckMetrics_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: check metrics - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckMetricsConfigFmt, checkName, `
+  metric {
+    name = "tt_connect"
+    type = "numeric"
+    units = "milliseconds"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("circonus_check_metrics.usage", "check", "circonus_check.usage", "id"),
+					resource.TestCheckResourceAttr("circonus_check_metrics.usage", "metric.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check_metrics.usage", "metric.*", map[string]string{
+						"name":  "tt_connect",
+						"type":  "numeric",
+						"units": "milliseconds",
+					}),
+					resource.TestCheckResourceAttr("circonus_check.usage", "external_metrics", "true"),
+					resource.TestCheckResourceAttr("circonus_check.usage", "metric.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckMetricsConfigFmt, checkName, `
+  metric {
+    name = "tt_connect"
+    type = "numeric"
+    units = "milliseconds"
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check_metrics.usage", "metric.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check_metrics.usage", "metric.*", map[string]string{
+						"name": "duration",
+						"type": "numeric",
+					}),
+				),
+			},
+			{
+				ResourceName:      "circonus_check_metrics.usage",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCheckMetricsMerge(t *testing.T) {
+	ms := "milliseconds"
+	current := []api.CheckBundleMetric{
+		{Name: "duration", Status: metricStatusActive, Type: "numeric", Tags: []string{}},
+		{Name: "code", Status: metricStatusActive, Type: "text", Tags: []string{}},
+		{Name: "tt_connect", Status: metricStatusAvailable, Type: "numeric", Tags: []string{}},
+	}
+	configured := []api.CheckBundleMetric{
+		{Name: "tt_firstbyte", Status: metricStatusActive, Type: "numeric", Tags: []string{}},
+		{Name: "tt_connect", Status: metricStatusActive, Type: "numeric", Units: &ms, Tags: []string{"team:web"}},
+		{Name: "bytes", Status: metricStatusActive, Type: "numeric", Tags: []string{}},
+	}
+
+	expected := []api.CheckBundleMetric{
+		{Name: "duration", Status: metricStatusAvailable, Type: "numeric", Tags: []string{}},
+		{Name: "code", Status: metricStatusAvailable, Type: "text", Tags: []string{}},
+		{Name: "tt_connect", Status: metricStatusActive, Type: "numeric", Units: &ms, Tags: []string{"team:web"}},
+		{Name: "bytes", Status: metricStatusActive, Type: "numeric", Tags: []string{}},
+		{Name: "tt_firstbyte", Status: metricStatusActive, Type: "numeric", Tags: []string{}},
+	}
+
+	if merged := checkMetricsMerge(current, configured); !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %#v, got %#v", expected, merged)
+	}
+
+	for _, m := range checkMetricsMerge(current, nil) {
+		if m.Status != metricStatusAvailable {
+			t.Errorf("expected metric %q to be deactivated, got status %q", m.Name, m.Status)
+		}
+	}
+}
+
+const testAccCirconusCheckMetricsConfigFmt = `
+resource "circonus_check" "usage" {
+  active = true
+  name = "%s"
+  period = "60s"
+  external_metrics = true
+
+  collector {
+    id = "/broker/1"
+  }
+
+  http {
+    url = "https://api.circonus.com/"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_check_metrics" "usage" {
+  check = circonus_check.usage.id
+%s
+}
+`
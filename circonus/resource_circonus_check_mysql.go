@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -32,13 +33,14 @@ var schemaCheckMySQL = &schema.Schema{
 			checkMySQLDSNAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkMySQLDSNAttr, `^.+$`),
 			},
 			checkMySQLQueryAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				StateFunc:    func(v interface{}) string { return strings.TrimSpace(v.(string)) },
-				ValidateFunc: validateRegexp(checkMySQLQueryAttr, `.+`),
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 		}),
 	},
@@ -0,0 +1,240 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.nad.* resource attribute names
+	checkNADPluginAttr = "plugin"
+	checkNADURLAttr    = "url"
+
+	// circonus_check.nad.plugin.* resource attribute names
+	checkNADPluginEnabledAttr = "enabled"
+	checkNADPluginNameAttr    = "name"
+)
+
+// checkNADPluginPrefix is the prefix of the check bundle config keys enabling
+// or disabling individual plugins, e.g. plugin_<name> = true
+const checkNADPluginPrefix = config.Key("plugin_")
+
+var checkNADDescriptions = attrDescrs{
+	checkNADPluginAttr: "Enable or disable individual NAD plugins, by default all plugins are collected",
+	checkNADURLAttr:    "The URL of the NAD agent, including the host and port (e.g. http://host:2609/)",
+}
+
+var checkNADPluginDescriptions = attrDescrs{
+	checkNADPluginEnabledAttr: "Whether the metrics of the plugin are collected",
+	checkNADPluginNameAttr:    "The name of the NAD plugin",
+}
+
+var schemaCheckNAD = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckNAD,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkNADDescriptions, map[schemaAttr]*schema.Schema{
+			checkNADPluginAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      hashCheckNADPlugin,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkNADPluginDescriptions, map[schemaAttr]*schema.Schema{
+						checkNADPluginEnabledAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						checkNADPluginNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkNADPluginNameAttr, `^[a-zA-Z0-9_.-]+$`),
+						},
+					}),
+				},
+			},
+			checkNADURLAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validateFuncs(
+					validateHTTPURL(checkNADURLAttr, urlIsAbs|urlWithPort),
+				),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateNAD reads the Config data out of circonusCheck.CheckBundle into
+// the statefile.
+func checkAPIToStateNAD(c *circonusCheck, d *schema.ResourceData) error {
+	nadConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, s := range c.Config {
+		swamp[k] = s
+	}
+
+	plugins := make([]interface{}, 0)
+	for k, v := range c.Config {
+		if !strings.HasPrefix(string(k), string(checkNADPluginPrefix)) {
+			continue
+		}
+
+		var enabled bool
+		switch v {
+		case "true", "on":
+			enabled = true
+		case "false", "off":
+			enabled = false
+		default:
+			log.Printf("PROVIDER BUG: unsupported value %q returned in key %q", v, k)
+			continue
+		}
+
+		plugins = append(plugins, map[string]interface{}{
+			string(checkNADPluginEnabledAttr): enabled,
+			string(checkNADPluginNameAttr):    strings.TrimPrefix(string(k), string(checkNADPluginPrefix)),
+		})
+		delete(swamp, k)
+	}
+	nadConfig[string(checkNADPluginAttr)] = schema.NewSet(hashCheckNADPlugin, plugins)
+
+	if s, ok := c.Config[config.URL]; ok && s != "" {
+		nadConfig[string(checkNADURLAttr)] = s
+	}
+	delete(swamp, config.URL)
+
+	// the port is derived from the url
+	delete(swamp, config.Port)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkNADAttr, schema.NewSet(hashCheckNAD, []interface{}{nadConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkNADAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckNAD creates a stable hash of the normalized values
+func hashCheckNAD(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	if pluginsRaw, ok := m[string(checkNADPluginAttr)]; ok {
+		var plugins []interface{}
+		switch u := pluginsRaw.(type) {
+		case *schema.Set:
+			plugins = u.List()
+		case []interface{}:
+			plugins = u
+		}
+
+		hashes := make([]int, 0, len(plugins))
+		for _, p := range plugins {
+			hashes = append(hashes, hashCheckNADPlugin(p))
+		}
+
+		sort.Ints(hashes)
+		for _, h := range hashes {
+			fmt.Fprintf(b, "%x", h)
+		}
+	}
+
+	writeString(checkNADURLAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+// hashCheckNADPlugin creates a stable hash of a single plugin
+func hashCheckNADPlugin(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	if v, ok := m[string(checkNADPluginEnabledAttr)]; ok {
+		fmt.Fprintf(b, "%t", v.(bool))
+	}
+
+	if v, ok := m[string(checkNADPluginNameAttr)]; ok {
+		fmt.Fprint(b, strings.TrimSpace(v.(string)))
+	}
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPINAD(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeNAD)
+
+	// Iterate over all `nad` attributes, even though we have a max of 1 in the
+	// schema.
+	for _, mapRaw := range l {
+		nadConfig := newInterfaceMap(mapRaw)
+
+		if v, found := nadConfig[checkNADPluginAttr]; found {
+			for _, pluginRaw := range v.(*schema.Set).List() {
+				plugin := newInterfaceMap(pluginRaw)
+				name := plugin[checkNADPluginNameAttr].(string)
+
+				k := checkNADPluginPrefix + config.Key(name)
+				if _, found := c.Config[k]; found {
+					return fmt.Errorf("duplicate %s %q", checkNADPluginAttr, name)
+				}
+				c.Config[k] = fmt.Sprintf("%t", plugin[checkNADPluginEnabledAttr].(bool))
+			}
+		}
+
+		if v, found := nadConfig[checkNADURLAttr]; found {
+			c.Config[config.URL] = v.(string)
+
+			u, err := url.Parse(v.(string))
+			if err != nil {
+				return fmt.Errorf("unable to parse %s %q: %w", checkNADURLAttr, v.(string), err)
+			}
+
+			if len(c.Target) == 0 {
+				c.Target = u.Hostname()
+			}
+
+			c.Config[config.Port] = u.Port()
+		}
+	}
+
+	return nil
+}
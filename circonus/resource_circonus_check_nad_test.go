@@ -0,0 +1,119 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckNAD_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Terraform test: NAD check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckNADConfigFmt, "true", checkName, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.nad", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "check_instances.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "check_instances.0.collector_id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "nad.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "nad.0.plugin.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "nad.0.url", "http://app1.example.org:2609/"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.nad", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "target", "app1.example.org"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "type", "json,nad"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckNADConfigFmt, "true", checkName, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.nad", "nad.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "nad.0.plugin.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckNADConfigFmt, "false", checkName, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.nad", "active", "false"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "metric.0.active", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckNADConfigFmt, "true", checkName, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.nad", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.nad", "metric.0.active", "true"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusCheckNADNoPortConfigFmt, checkName),
+				ExpectError: regexp.MustCompile(`Port is missing from URL`),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckNADConfigFmt = `
+resource "circonus_check" "nad" {
+  active = %s
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  nad {
+    url = "http://app1.example.org:2609/"
+
+    plugin {
+      name = "cpu"
+    }
+
+    plugin {
+      name = "disk"
+      enabled = %s
+    }
+  }
+
+  metric {
+    name = "cpu` + "`" + `user"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
+
+const testAccCirconusCheckNADNoPortConfigFmt = `
+resource "circonus_check" "nad" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  nad {
+    url = "http://app1.example.org/"
+  }
+
+  metric {
+    name = "cpu` + "`" + `user"
+    type = "numeric"
+  }
+
+  tags = [ "author:terraform", "lifecycle:unittest" ]
+}
+`
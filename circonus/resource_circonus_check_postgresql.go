@@ -3,34 +3,47 @@ package circonus
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	// circonus_check.postgresql.* resource attribute names
-	checkPostgreSQLDSNAttr = "dsn"
-	// checkPostgreSQLHostAttr      = "host"
-	// checkPostgreSQLNameAttr      = "name"
-	// checkPostgreSQLPasswordAttr  = "password"
-	// checkPostgreSQLPortAttr      = "port"
-	checkPostgreSQLQueryAttr = "query"
-	// checkPostgreSQLSSLModeAttr   = "sslmode"
-	// checkPostgreSQLUserAttr      = "user"
+	checkPostgreSQLDSNAttr      = "dsn"
+	checkPostgreSQLHostAttr     = "host"
+	checkPostgreSQLNameAttr     = "name"
+	checkPostgreSQLPasswordAttr = "password"
+	checkPostgreSQLPortAttr     = "port"
+	checkPostgreSQLQueryAttr    = "query"
+	checkPostgreSQLSSLModeAttr  = "sslmode"
+	checkPostgreSQLUserAttr     = "user"
 )
 
 var checkPostgreSQLDescriptions = attrDescrs{
-	checkPostgreSQLDSNAttr: "The connect DSN for the PostgreSQL instance",
-	// checkPostgreSQLHostAttr:     "The Hostname to connect to",
-	// checkPostgreSQLNameAttr:     "The database name to connect to",
-	// checkPostgreSQLPasswordAttr: "The password to use",
-	// checkPostgreSQLPortAttr:     "The TCP port number to use to connect on",
-	checkPostgreSQLQueryAttr: "The SQL to use as the query",
-	// checkPostgreSQLSSLModeAttr:  "The SSL Mode to connect as",
-	// checkPostgreSQLUserAttr:     "The username to connect as",
+	checkPostgreSQLDSNAttr:      "The connect DSN for the PostgreSQL instance, an alternative to the individual connect options",
+	checkPostgreSQLHostAttr:     "The Hostname to connect to",
+	checkPostgreSQLNameAttr:     "The database name to connect to",
+	checkPostgreSQLPasswordAttr: "The password to use",
+	checkPostgreSQLPortAttr:     "The TCP port number to use to connect on",
+	checkPostgreSQLQueryAttr:    "The SQL to use as the query",
+	checkPostgreSQLSSLModeAttr:  "The SSL Mode to connect as",
+	checkPostgreSQLUserAttr:     "The username to connect as",
+}
+
+// checkPostgreSQLDSNKeys maps the connect options to their libpq DSN keys.
+var checkPostgreSQLDSNKeys = map[schemaAttr]string{
+	checkPostgreSQLHostAttr:     "host",
+	checkPostgreSQLNameAttr:     "dbname",
+	checkPostgreSQLPasswordAttr: "password",
+	checkPostgreSQLPortAttr:     "port",
+	checkPostgreSQLSSLModeAttr:  "sslmode",
+	checkPostgreSQLUserAttr:     "user",
 }
 
 var schemaCheckPostgreSQL = &schema.Schema{
@@ -43,54 +56,49 @@ var schemaCheckPostgreSQL = &schema.Schema{
 		Schema: convertToHelperSchema(checkPostgreSQLDescriptions, map[schemaAttr]*schema.Schema{
 			checkPostgreSQLDSNAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkPostgreSQLDSNAttr, `^.+$`),
 			},
-			// TODO(sean@): Parse out the DSN into individual PostgreSQL connect
-			// options.
-			//
-			// checkPostgreSQLHostAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Optional:     true,
-			// 	Default:      "/tmp",
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLHostAttr, `^(/.+|[\S]+)$`),
-			// },
-			// checkPostgreSQLNameAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Required:     true,
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLNameAttr, `^[\S]+$`),
-			// },
-			// checkPostgreSQLPasswordAttr: &schema.Schema{
-			// 	Type:      schema.TypeString,
-			// 	Optional:  true,
-			// 	Sensitive: true,
-			// },
-			// checkPostgreSQLPortAttr: &schema.Schema{
-			// 	Type:     schema.TypeInt,
-			// 	Optional: true,
-			// 	Default:  5432,
-			// 	ValidateFunc: validateFuncs(
-			// 		validateIntMin(checkPostgreSQLPortAttr, 1),
-			// 		validateIntMax(checkPostgreSQLPortAttr, 65535),
-			// 	),
-			// },
+			checkPostgreSQLHostAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLHostAttr, `^(/.+|[\S]+)$`),
+			},
+			checkPostgreSQLNameAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLNameAttr, `^[\S]+$`),
+			},
+			checkPostgreSQLPasswordAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			checkPostgreSQLPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkPostgreSQLPortAttr, 1),
+					validateIntMax(checkPostgreSQLPortAttr, 65535),
+				),
+			},
 			checkPostgreSQLQueryAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				StateFunc:    suppressWhitespace,
-				ValidateFunc: validateRegexp(checkPostgreSQLQueryAttr, `.+`),
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			checkPostgreSQLSSLModeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLSSLModeAttr, `^(disable|allow|prefer|require|verify-ca|verify-full)$`),
+			},
+			checkPostgreSQLUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLUserAttr, `.+`),
 			},
-			// checkPostgreSQLSSLModeAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Optional:     true,
-			// 	Default:      "require",
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLSSLModeAttr, `^(disable|require|verify-ca|verify-full)$`),
-			// },
-			// checkPostgreSQLUserAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Required:     true,
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLUserAttr, `.+`),
-			// },
 		}),
 	},
 }
@@ -100,8 +108,35 @@ var schemaCheckPostgreSQL = &schema.Schema{
 func checkAPIToStatePostgreSQL(c *circonusCheck, d *schema.ResourceData) error {
 	postgresqlConfig := make(map[string]interface{}, len(c.Config))
 
-	// TODO(sean@): Parse out the DSN into individual PostgreSQL connect options
-	postgresqlConfig[string(checkPostgreSQLDSNAttr)] = c.Config[config.DSN]
+	// the DSN is split into the connect options when they were configured,
+	// imported checks use the DSN
+	if checkPostgreSQLConfiguredParts(d) {
+		parts, err := checkPostgreSQLParseDSN(c.Config[config.DSN])
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.DSN, err)
+		}
+
+		for attr, key := range checkPostgreSQLDSNKeys {
+			v, found := parts[key]
+			if !found {
+				continue
+			}
+
+			if attr == checkPostgreSQLPortAttr {
+				port, err := strconv.Atoi(v)
+				if err != nil {
+					return fmt.Errorf("unable to parse the %s of %s: %w", key, config.DSN, err)
+				}
+				postgresqlConfig[string(attr)] = port
+				continue
+			}
+
+			postgresqlConfig[string(attr)] = v
+		}
+	} else {
+		postgresqlConfig[string(checkPostgreSQLDSNAttr)] = c.Config[config.DSN]
+	}
+
 	postgresqlConfig[string(checkPostgreSQLQueryAttr)] = c.Config[config.SQL]
 
 	if err := d.Set(checkPostgreSQLAttr, schema.NewSet(hashCheckPostgreSQL, []interface{}{postgresqlConfig})); err != nil {
@@ -111,17 +146,34 @@ func checkAPIToStatePostgreSQL(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
+// checkPostgreSQLConfiguredParts returns true when the postgresql block uses
+// the individual connect options instead of the DSN.
+func checkPostgreSQLConfiguredParts(d *schema.ResourceData) bool {
+	s, ok := d.Get(checkPostgreSQLAttr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return false
+	}
+
+	postgresqlConfig, ok := s.List()[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	dsn, _ := postgresqlConfig[string(checkPostgreSQLDSNAttr)].(string)
+	return dsn == ""
+}
+
 // hashCheckPostgreSQL creates a stable hash of the normalized values
 func hashCheckPostgreSQL(v interface{}) int {
 	m := v.(map[string]interface{})
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 
-	// writeInt := func(attrName schemaAttr) {
-	// 	if v, ok := m[string(attrName)]; ok {
-	// 		fmt.Fprintf(b, "%x", v.(int))
-	// 	}
-	// }
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(int) != 0 {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
 
 	writeString := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
@@ -132,13 +184,13 @@ func hashCheckPostgreSQL(v interface{}) int {
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
 	writeString(checkPostgreSQLDSNAttr)
-	// writeString(checkPostgreSQLHostAttr)
-	// writeString(checkPostgreSQLNameAttr)
-	// writeString(checkPostgreSQLPasswordAttr)
-	// writeInt(checkPostgreSQLPortAttr)
-	// writeString(checkPostgreSQLSSLModeAttr)
+	writeString(checkPostgreSQLHostAttr)
+	writeString(checkPostgreSQLNameAttr)
+	writeString(checkPostgreSQLPasswordAttr)
+	writeInt(checkPostgreSQLPortAttr)
 	writeString(checkPostgreSQLQueryAttr)
-	// writeString(checkPostgreSQLUserAttr)
+	writeString(checkPostgreSQLSSLModeAttr)
+	writeString(checkPostgreSQLUserAttr)
 
 	s := b.String()
 	return hashcode.String(s)
@@ -152,8 +204,30 @@ func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error {
 	for _, mapRaw := range l {
 		postgresConfig := newInterfaceMap(mapRaw)
 
-		if v, found := postgresConfig[checkPostgreSQLDSNAttr]; found {
-			c.Config[config.DSN] = v.(string)
+		parts := make(map[string]string, len(checkPostgreSQLDSNKeys))
+		for attr, key := range checkPostgreSQLDSNKeys {
+			switch v := postgresConfig[string(attr)].(type) {
+			case string:
+				if v != "" {
+					parts[key] = v
+				}
+			case int:
+				if v != 0 {
+					parts[key] = strconv.Itoa(v)
+				}
+			}
+		}
+
+		dsn, _ := postgresConfig[string(checkPostgreSQLDSNAttr)].(string)
+		switch {
+		case dsn != "" && len(parts) > 0:
+			return fmt.Errorf("%s conflicts with the individual connect options (e.g. %s and %s)", checkPostgreSQLDSNAttr, checkPostgreSQLHostAttr, checkPostgreSQLUserAttr)
+		case dsn != "":
+			c.Config[config.DSN] = dsn
+		case parts[checkPostgreSQLDSNKeys[checkPostgreSQLNameAttr]] == "" || parts[checkPostgreSQLDSNKeys[checkPostgreSQLUserAttr]] == "":
+			return fmt.Errorf("either %s or both %s and %s must be set", checkPostgreSQLDSNAttr, checkPostgreSQLNameAttr, checkPostgreSQLUserAttr)
+		default:
+			c.Config[config.DSN] = checkPostgreSQLFormatDSN(parts)
 		}
 
 		if v, found := postgresConfig[checkPostgreSQLQueryAttr]; found {
@@ -163,3 +237,73 @@ func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error {
 
 	return nil
 }
+
+// checkPostgreSQLFormatDSN returns the libpq DSN of the connect options, the
+// keys are sorted and the values quoted when necessary.
+func checkPostgreSQLFormatDSN(parts map[string]string) string {
+	keys := make([]string, 0, len(parts))
+	for k := range parts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := parts[k]
+		if v == "" || strings.ContainsAny(v, ` '\`) {
+			v = `'` + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + `'`
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// checkPostgreSQLParseDSN returns the key/value pairs of a libpq DSN, e.g.
+// "host=db port=5432 password='a b'".
+func checkPostgreSQLParseDSN(dsn string) (map[string]string, error) {
+	parts := make(map[string]string)
+
+	s := strings.TrimSpace(dsn)
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 1 {
+			return nil, fmt.Errorf("missing the value of %q", s)
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(s, "'") {
+			i, closed := 1, false
+			for ; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					value.WriteByte(s[i])
+					continue
+				}
+				if s[i] == '\'' {
+					closed = true
+					break
+				}
+				value.WriteByte(s[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted value of %q", key)
+			}
+			s = s[i+1:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			value.WriteString(s[:end])
+			s = s[end:]
+		}
+
+		parts[key] = value.String()
+		s = strings.TrimLeft(s, " ")
+	}
+
+	return parts, nil
+}
@@ -2,10 +2,13 @@ package circonus
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckPostgreSQL_basic(t *testing.T) {
@@ -61,6 +64,80 @@ func TestAccCirconusCheckPostgreSQL_basic(t *testing.T) {
 	})
 }
 
+func TestCheckPostgreSQLDSN(t *testing.T) {
+	parts := map[string]string{
+		"dbname":   "app",
+		"host":     "pg1.example.org",
+		"password": `it's a secret`,
+		"port":     "5432",
+		"user":     "postgres",
+	}
+
+	dsn := checkPostgreSQLFormatDSN(parts)
+	if expected := `dbname=app host=pg1.example.org password='it\'s a secret' port=5432 user=postgres`; dsn != expected {
+		t.Errorf("expected %q, got %q", expected, dsn)
+	}
+
+	parsed, err := checkPostgreSQLParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, parts) {
+		t.Errorf("expected %v, got %v", parts, parsed)
+	}
+
+	for _, dsn := range []string{"host", "password='open"} {
+		if _, err := checkPostgreSQLParseDSN(dsn); err == nil {
+			t.Errorf("expected an error parsing %q", dsn)
+		}
+	}
+}
+
+func TestCheckPostgreSQLConnectOptions(t *testing.T) {
+	postgresqlConfig := map[string]interface{}{
+		string(checkPostgreSQLDSNAttr):      "",
+		string(checkPostgreSQLHostAttr):     "pg1.example.org",
+		string(checkPostgreSQLNameAttr):     "app",
+		string(checkPostgreSQLPasswordAttr): "12345",
+		string(checkPostgreSQLPortAttr):     5433,
+		string(checkPostgreSQLQueryAttr):    "SELECT 'sessions', count(*) AS active FROM pg_stat_activity",
+		string(checkPostgreSQLSSLModeAttr):  "require",
+		string(checkPostgreSQLUserAttr):     "postgres",
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIPostgreSQL(&c, interfaceList{postgresqlConfig}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := "dbname=app host=pg1.example.org password=12345 port=5433 sslmode=require user=postgres"; c.Config[config.DSN] != expected {
+		t.Errorf("expected %s %q, got %q", config.DSN, expected, c.Config[config.DSN])
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		checkPostgreSQLAttr: []interface{}{postgresqlConfig},
+	})
+	if err := checkAPIToStatePostgreSQL(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(checkPostgreSQLAttr).(*schema.Set).List()[0].(map[string]interface{})
+	if hashCheckPostgreSQL(state) != hashCheckPostgreSQL(postgresqlConfig) {
+		t.Errorf("expected the connect options to round trip, got %v", state)
+	}
+
+	invalid := []map[string]interface{}{
+		{string(checkPostgreSQLDSNAttr): "user=postgres", string(checkPostgreSQLHostAttr): "pg1.example.org"},
+		{string(checkPostgreSQLHostAttr): "pg1.example.org", string(checkPostgreSQLUserAttr): "postgres"},
+	}
+	for _, postgresqlConfig := range invalid {
+		c := newCheck()
+		if err := checkConfigToAPIPostgreSQL(&c, interfaceList{postgresqlConfig}); err == nil {
+			t.Errorf("expected an error for %v", postgresqlConfig)
+		}
+	}
+}
+
 const testAccCirconusCheckPostgreSQLConfigFmt = `
 variable "test_tags" {
   type = "list"
@@ -53,13 +53,13 @@ var schemaCheckRedis = &schema.Schema{
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkRedisPasswordAttr, `.+`),
 			},
-			checkTCPPortAttr: {
+			checkRedisPortAttr: {
 				Type:     schema.TypeInt,
 				Optional: true,
 				Default:  6379,
 				ValidateFunc: validateFuncs(
-					validateIntMin(checkTCPPortAttr, 0),
-					validateIntMax(checkTCPPortAttr, 65535),
+					validateIntMin(checkRedisPortAttr, 1),
+					validateIntMax(checkRedisPortAttr, 65535),
 				),
 			},
 		}),
@@ -23,6 +23,12 @@ func TestAccCirconusCheckRedis_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.redis", "collector.#", "1"),
 					resource.TestCheckResourceAttr("circonus_check.redis", "collector.0.id", "/broker/1"),
 					resource.TestCheckResourceAttr("circonus_check.redis", "redis.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("circonus_check.redis", "redis.*", map[string]string{
+						"command":  "INFO",
+						"db_index": "2",
+						"password": "s3cr3t",
+						"port":     "6380",
+					}),
 					resource.TestCheckResourceAttr("circonus_check.redis", "name", checkName),
 					resource.TestCheckResourceAttr("circonus_check.redis", "notes", "Check to grab redis metrics"),
 					resource.TestCheckResourceAttr("circonus_check.redis", "period", "60s"),
@@ -54,6 +60,9 @@ resource "circonus_check" "redis" {
   }
 
   redis {
+    db_index = 2
+    password = "s3cr3t"
+    port     = 6380
   }
 
   metric_filter {
@@ -32,11 +32,17 @@ const (
 	checkSNMPVersion           = "version"
 )
 
+const (
+	checkSNMPVersion3           = "3"
+	checkSNMPSecurityAuthNoPriv = "authNoPriv"
+	checkSNMPSecurityAuthPriv   = "authPriv"
+)
+
 var checkSNMPDescriptions = attrDescrs{
 
 	checkSNMPAuthPassphrase:    "The authentication passphrase to use. Only applicaable to SNMP Version 3.",
 	checkSNMPAuthProtocol:      "The authentication protocol to use. Only applicaable to SNMP Version 3.",
-	checkSNMPCommunity:         "The SNMP community string providing read access.",
+	checkSNMPCommunity:         "The SNMP community string providing read access. Required for SNMP Versions 1 and 2c.",
 	checkSNMPContextEngine:     "The context engine hex value to use. Only applicaable to SNMP Version 3.",
 	checkSNMPContextName:       "The context name to use. Only applicaable to SNMP Version 3.",
 	checkSNMPOID:               "Defines a metric to query.",
@@ -51,7 +57,7 @@ var checkSNMPDescriptions = attrDescrs{
 }
 
 var checkSNMPOIDDescriptions = attrDescrs{
-	checkSNMPOIDName: "Name of the metric produced by this MIB.",
+	checkSNMPOIDName: "Name of the metric produced by this MIB, unique within the check.",
 	checkSNMPOIDPath: "The decimal notation or MIB name of this OID.",
 	checkSNMPOIDType: "The metric type of this OID. The value can be either one of the single letter codes in the metric_type_t enum or the following string variants: guess, int32, uint32, int64, uint64, double, string.",
 }
@@ -66,23 +72,25 @@ var schemaCheckSNMP = &schema.Schema{
 			checkSNMPAuthPassphrase: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkSNMPAuthPassphrase, `.+`),
 			},
 			checkSNMPAuthProtocol: {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validateRegexp(checkSNMPAuthProtocol, `(MD5|SHA)`),
+				ValidateFunc: validateStringIn(checkSNMPAuthProtocol, validCheckSNMPAuthProtocols),
 				Default:      "MD5",
 			},
 			checkSNMPCommunity: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkSNMPCommunity, `.+`),
 			},
 			checkSNMPContextEngine: {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validateRegexp(checkSNMPContextEngine, `[0-9a-fA-F]+`),
+				ValidateFunc: validateRegexp(checkSNMPContextEngine, `^[0-9a-fA-F]+$`),
 			},
 			checkSNMPContextName: {
 				Type:         schema.TypeString,
@@ -97,23 +105,24 @@ var schemaCheckSNMP = &schema.Schema{
 			checkSNMPPrivacyPassphrase: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkSNMPPrivacyPassphrase, `.+`),
 			},
 			checkSNMPPrivacyProtocol: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Default:      "DES",
-				ValidateFunc: validateRegexp(checkSNMPPrivacyProtocol, `(DES|AES128|AES)`),
+				ValidateFunc: validateStringIn(checkSNMPPrivacyProtocol, validCheckSNMPPrivacyProtocols),
 			},
 			checkSNMPSecurityEngine: {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validateRegexp(checkSNMPSecurityEngine, `[0-9a-fA-F]+`),
+				ValidateFunc: validateRegexp(checkSNMPSecurityEngine, `^[0-9a-fA-F]+$`),
 			},
 			checkSNMPSecurityLevel: {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ValidateFunc: validateRegexp(checkSNMPSecurityLevel, `(noAuthNoPriv|authNoPriv|authPriv)`),
+				ValidateFunc: validateStringIn(checkSNMPSecurityLevel, validCheckSNMPSecurityLevels),
 				Default:      "authPriv",
 			},
 			checkSNMPSecurityName: {
@@ -129,7 +138,7 @@ var schemaCheckSNMP = &schema.Schema{
 			checkSNMPVersion: {
 				Type:         schema.TypeString,
 				Required:     true,
-				ValidateFunc: validateRegexp(checkSNMPVersion, `(1|2c|3)`),
+				ValidateFunc: validateStringIn(checkSNMPVersion, validCheckSNMPVersions),
 			},
 			checkSNMPOID: {
 				Type:     schema.TypeList,
@@ -220,20 +229,12 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 	saveBoolConfigToState(config.SeparateQueries, checkSNMPSeparateQueries)
 	saveStringConfigToState(config.Version, checkSNMPVersion)
 
-	// count the number of oids in the config so we can make our list
-	var oid_count = 0
-	for k := range c.Config {
-		key := string(k)
-		if strings.HasPrefix(key, string(config.OIDPrefix)) {
-			oid_count++
-		}
-	}
-	oid_list := make([]interface{}, 0)
+	oidList := make([]interface{}, 0)
 	for k, v := range c.Config {
 		key := string(k)
 		if strings.HasPrefix(key, string(config.OIDPrefix)) {
 			oidProps := make(map[string]interface{})
-			name := key[4:]
+			name := key[len(config.OIDPrefix):]
 			oidProps[string(checkSNMPOIDName)] = name
 			oidProps[string(checkSNMPOIDPath)] = v
 
@@ -243,19 +244,28 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 				delete(swamp, config.Key(t))
 			}
 			delete(swamp, k)
-			oid_list = append(oid_list, oidProps)
+			oidList = append(oidList, oidProps)
 		}
 	}
 
-	sort.Slice(oid_list, func(i, j int) bool {
-		if oid_list[i] != nil && oid_list[j] != nil {
-			y := oid_list[i].(map[string]interface{})
-			z := oid_list[j].(map[string]interface{})
-			return y[string(checkSNMPOIDName)].(string) < z[string(checkSNMPOIDName)].(string)
+	// The API stores the OIDs as unordered config keys, keep the order of the
+	// configured OIDs and sort the others by name after them.
+	order := checkSNMPConfiguredOIDOrder(d)
+	sort.SliceStable(oidList, func(i, j int) bool {
+		y := oidList[i].(map[string]interface{})[string(checkSNMPOIDName)].(string)
+		z := oidList[j].(map[string]interface{})[string(checkSNMPOIDName)].(string)
+		yPos, yFound := order[y]
+		zPos, zFound := order[z]
+		switch {
+		case yFound && zFound:
+			return yPos < zPos
+		case yFound != zFound:
+			return yFound
+		default:
+			return y < z
 		}
-		return true
 	})
-	snmpConfig[string(checkSNMPOID)] = oid_list
+	snmpConfig[string(checkSNMPOID)] = oidList
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
@@ -287,6 +297,10 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error {
 	for _, mapRaw := range l {
 		snmpConfig := newInterfaceMap(mapRaw)
 
+		if err := checkSNMPValidateVersion(snmpConfig); err != nil {
+			return err
+		}
+
 		if v, found := snmpConfig[checkSNMPAuthPassphrase]; found {
 			c.Config[config.AuthPassphrase] = v.(string)
 		}
@@ -335,15 +349,88 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error {
 
 		if v, found := snmpConfig[checkSNMPOID]; found {
 			m := v.([]interface{})
+			names := make(map[string]struct{}, len(m))
 			for _, ll := range m {
 				if ll == nil {
 					continue
 				}
 				n := ll.(map[string]interface{})
-				c.Config[config.Key(fmt.Sprintf("oid_%s", n[string(checkSNMPOIDName)].(string)))] = n[string(checkSNMPOIDPath)].(string)
-				c.Config[config.Key(fmt.Sprintf("type_%s", n[string(checkSNMPOIDName)].(string)))] = n[string(checkSNMPOIDType)].(string)
+				name := n[string(checkSNMPOIDName)].(string)
+				if _, dup := names[name]; dup {
+					return fmt.Errorf("%s %s %q is used by more than one %s", checkSNMPOID, checkSNMPOIDName, name, checkSNMPOID)
+				}
+				names[name] = struct{}{}
+
+				c.Config[config.Key(string(config.OIDPrefix)+name)] = n[string(checkSNMPOIDPath)].(string)
+				if t, _ := n[string(checkSNMPOIDType)].(string); t != "" {
+					c.Config[config.Key(string(config.TypePrefix)+name)] = t
+				}
 			}
 		}
 	}
 	return nil
 }
+
+// checkSNMPValidateVersion verifies the attributes required by the configured
+// SNMP version: a community for versions 1 and 2c, a security name and the
+// passphrases required by the security level for version 3.
+func checkSNMPValidateVersion(snmpConfig interfaceMap) error {
+	version, _ := snmpConfig[checkSNMPVersion].(string)
+	isSet := func(attrName schemaAttr) bool {
+		v, _ := snmpConfig[string(attrName)].(string)
+		return v != ""
+	}
+
+	if version != checkSNMPVersion3 {
+		if !isSet(checkSNMPCommunity) {
+			return fmt.Errorf("%s is required for SNMP version %s", checkSNMPCommunity, version)
+		}
+
+		for _, attrName := range []schemaAttr{checkSNMPAuthPassphrase, checkSNMPContextEngine, checkSNMPContextName, checkSNMPPrivacyPassphrase, checkSNMPSecurityEngine, checkSNMPSecurityName} {
+			if isSet(attrName) {
+				return fmt.Errorf("%s is only supported by SNMP version %s", attrName, checkSNMPVersion3)
+			}
+		}
+
+		return nil
+	}
+
+	if !isSet(checkSNMPSecurityName) {
+		return fmt.Errorf("%s is required for SNMP version %s", checkSNMPSecurityName, checkSNMPVersion3)
+	}
+
+	level, _ := snmpConfig[checkSNMPSecurityLevel].(string)
+	if (level == checkSNMPSecurityAuthNoPriv || level == checkSNMPSecurityAuthPriv) && !isSet(checkSNMPAuthPassphrase) {
+		return fmt.Errorf("%s is required for %s %q", checkSNMPAuthPassphrase, checkSNMPSecurityLevel, level)
+	}
+
+	if level == checkSNMPSecurityAuthPriv && !isSet(checkSNMPPrivacyPassphrase) {
+		return fmt.Errorf("%s is required for %s %q", checkSNMPPrivacyPassphrase, checkSNMPSecurityLevel, level)
+	}
+
+	return nil
+}
+
+// checkSNMPConfiguredOIDOrder returns the position of each configured OID by
+// name.
+func checkSNMPConfiguredOIDOrder(d *schema.ResourceData) map[string]int {
+	l, ok := d.Get(checkSNMPAttr).([]interface{})
+	if !ok || len(l) == 0 {
+		return nil
+	}
+
+	snmpConfig, ok := l[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	oids, _ := snmpConfig[string(checkSNMPOID)].([]interface{})
+	order := make(map[string]int, len(oids))
+	for i, oid := range oids {
+		if m, ok := oid.(map[string]interface{}); ok {
+			order[m[string(checkSNMPOIDName)].(string)] = i
+		}
+	}
+
+	return order
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckSNMP_basic(t *testing.T) {
@@ -45,6 +46,80 @@ func TestAccCirconusCheckSNMP_basic(t *testing.T) {
 	})
 }
 
+func TestCheckSNMPVersions(t *testing.T) {
+	tests := []struct {
+		config   map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{checkSNMPVersion: "2c", checkSNMPCommunity: "public"}, true},
+		{map[string]interface{}{checkSNMPVersion: "1"}, false},
+		{map[string]interface{}{checkSNMPVersion: "2c", checkSNMPCommunity: "public", checkSNMPSecurityName: "admin"}, false},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityName: "admin", checkSNMPSecurityLevel: "noAuthNoPriv"}, true},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityLevel: "noAuthNoPriv"}, false},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityName: "admin", checkSNMPSecurityLevel: "authNoPriv"}, false},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityName: "admin", checkSNMPSecurityLevel: "authNoPriv", checkSNMPAuthPassphrase: "foo"}, true},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityName: "admin", checkSNMPSecurityLevel: "authPriv", checkSNMPAuthPassphrase: "foo"}, false},
+		{map[string]interface{}{checkSNMPVersion: "3", checkSNMPSecurityName: "admin", checkSNMPSecurityLevel: "authPriv", checkSNMPAuthPassphrase: "foo", checkSNMPPrivacyPassphrase: "bar"}, true},
+	}
+
+	for _, test := range tests {
+		c := newCheck()
+		err := checkConfigToAPISNMP(&c, interfaceList{test.config})
+		if test.expected && err != nil {
+			t.Errorf("unexpected error for %v: %v", test.config, err)
+		}
+		if !test.expected && err == nil {
+			t.Errorf("expected an error for %v", test.config)
+		}
+	}
+}
+
+func TestCheckSNMPOIDOrder(t *testing.T) {
+	oids := []interface{}{
+		map[string]interface{}{string(checkSNMPOIDName): "upsBatVoltage", string(checkSNMPOIDPath): ".1.3.6.1.4.1.318.1.1.1.2.2.8.0", string(checkSNMPOIDType): ""},
+		map[string]interface{}{string(checkSNMPOIDName): "upsBatCapacity", string(checkSNMPOIDPath): ".1.3.6.1.4.1.318.1.1.1.2.2.1.0", string(checkSNMPOIDType): "int32"},
+	}
+	snmpConfig := map[string]interface{}{
+		string(checkSNMPCommunity): "public",
+		string(checkSNMPOID):       oids,
+		string(checkSNMPVersion):   "2c",
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPISNMP(&c, interfaceList{snmpConfig}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, found := c.Config[config.Key(string(config.TypePrefix)+"upsBatVoltage")]; found {
+		t.Errorf("expected no type for OID upsBatVoltage, got %q", c.Config[config.Key(string(config.TypePrefix)+"upsBatVoltage")])
+	}
+
+	// an OID added outside of terraform is sorted after the configured ones
+	c.Config[config.Key(string(config.OIDPrefix)+"upsAdvBatTemperature")] = ".1.3.6.1.4.1.318.1.1.1.2.2.2.0"
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		checkSNMPAttr: []interface{}{snmpConfig},
+	})
+	if err := checkAPIToStateSNMP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, name := range []string{"upsBatVoltage", "upsBatCapacity", "upsAdvBatTemperature"} {
+		if got := d.Get(fmt.Sprintf("%s.0.%s.%d.%s", checkSNMPAttr, checkSNMPOID, i, checkSNMPOIDName)); got != name {
+			t.Errorf("expected OID %d to be %q, got %q", i, name, got)
+		}
+	}
+
+	duplicate := map[string]interface{}{
+		string(checkSNMPCommunity): "public",
+		string(checkSNMPOID):       append(oids, oids[0]),
+		string(checkSNMPVersion):   "2c",
+	}
+	if err := checkConfigToAPISNMP(&c, interfaceList{duplicate}); err == nil {
+		t.Errorf("expected an error for a duplicate OID name")
+	}
+}
+
 const testAccCirconusCheckSNMPConfigFmt = `
 variable "test_tags" {
   type = "list"
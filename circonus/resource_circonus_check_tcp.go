@@ -10,6 +10,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -35,6 +36,22 @@ var checkTCPDescriptions = attrDescrs{
 	checkTCPTLSAttr:          "Upgrade TCP connection to use TLS.",
 }
 
+// checkTCPMetricNames are the metrics collected by a tcp check, the connection
+// timings are tt_connect and tt_firstbyte.
+var checkTCPMetricNames = map[string]struct{}{
+	"banner":       {},
+	"banner_match": {},
+	"cert_end":     {},
+	"cert_end_in":  {},
+	"cert_error":   {},
+	"cert_issuer":  {},
+	"cert_start":   {},
+	"cert_subject": {},
+	"duration":     {},
+	"tt_connect":   {},
+	"tt_firstbyte": {},
+}
+
 var schemaCheckTCP = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -44,9 +61,12 @@ var schemaCheckTCP = &schema.Schema{
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkTCPDescriptions, map[schemaAttr]*schema.Schema{
 			checkTCPBannerRegexpAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validateRegexp(checkTCPBannerRegexpAttr, `.+`),
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateRegexp(checkTCPBannerRegexpAttr, `.+`),
+					validation.StringIsValidRegExp,
+				),
 			},
 			checkTCPCAChainAttr: {
 				Type:         schema.TypeString,
@@ -77,7 +97,7 @@ var schemaCheckTCP = &schema.Schema{
 				Type:     schema.TypeInt,
 				Required: true,
 				ValidateFunc: validateFuncs(
-					validateIntMin(checkTCPPortAttr, 0),
+					validateIntMin(checkTCPPortAttr, 1),
 					validateIntMax(checkTCPPortAttr, 65535),
 				),
 			},
@@ -162,6 +182,15 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 		}
 	}
 
+	// Metrics with other names are never collected by a tcp check, a common
+	// mistake is naming the connection timings, which are tt_connect and
+	// tt_firstbyte.
+	for _, m := range c.Metrics {
+		if _, ok := checkTCPMetricNames[m.Name]; !ok {
+			log.Printf("[WARN] metric %q of check %q is not collected by tcp checks, timings are reported as tt_connect (time to connect) and tt_firstbyte (time to the first byte)", m.Name, c.CID)
+		}
+	}
+
 	if err := d.Set(checkTCPAttr, schema.NewSet(hashCheckTCP, []interface{}{tcpConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkTCPAttr, err)
 	}
@@ -247,6 +276,15 @@ func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error {
 		if v, found := tcpConfig[checkTCPTLSAttr]; found {
 			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
 		}
+
+		// the TLS options are ignored by the broker unless tls is enabled
+		if tls, _ := tcpConfig[checkTCPTLSAttr].(bool); !tls {
+			for _, attr := range []schemaAttr{checkTCPCAChainAttr, checkTCPCertFileAttr, checkTCPCiphersAttr, checkTCPKeyFileAttr} {
+				if v, found := tcpConfig[string(attr)]; found && v.(string) != "" {
+					return fmt.Errorf("%s requires %s to be enabled", attr, checkTCPTLSAttr)
+				}
+			}
+		}
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -73,12 +74,37 @@ func TestAccCirconusCheckTCP_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.tls_cert", "tags.3", "source:fastly"),
 					resource.TestCheckResourceAttr("circonus_check.tls_cert", "target", "127.0.0.1"),
 					resource.TestCheckResourceAttr("circonus_check.tls_cert", "type", "tcp"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "cert_metric_names.#", "6"),
+					resource.TestCheckResourceAttr("circonus_check.tls_cert", "cert_metric_names.1", "cert_end_in"),
 				),
 			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusCheckTCPInvalidBannerConfigFmt, checkName),
+				ExpectError: regexp.MustCompile(`error parsing regexp`),
+			},
 		},
 	})
 }
 
+func TestCheckTCPTLSOptions(t *testing.T) {
+	tests := []struct {
+		tcpConfig map[string]interface{}
+		valid     bool
+	}{
+		{map[string]interface{}{string(checkTCPCAChainAttr): "/etc/ssl/ca.pem", string(checkTCPTLSAttr): true}, true},
+		{map[string]interface{}{string(checkTCPCAChainAttr): "/etc/ssl/ca.pem", string(checkTCPTLSAttr): false}, false},
+		{map[string]interface{}{string(checkTCPCiphersAttr): "", string(checkTCPTLSAttr): false}, true},
+	}
+
+	for _, test := range tests {
+		c := newCheck()
+		err := checkConfigToAPITCP(&c, interfaceList{test.tcpConfig})
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%v: expected valid %t, got %v", test.tcpConfig, test.valid, err)
+		}
+	}
+}
+
 const testAccCirconusCheckTCPConfigFmt = `
 variable "tcp_check_tags" {
   type = "list"
@@ -148,3 +174,25 @@ resource "circonus_check" "tls_cert" {
   tags = "${var.tcp_check_tags}"
 }
 `
+
+const testAccCirconusCheckTCPInvalidBannerConfigFmt = `
+resource "circonus_check" "tls_cert" {
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  tcp {
+    host = "127.0.0.1"
+    port = 22
+    banner_regexp = "^SSH-(2.0"
+  }
+
+  metric {
+    name = "banner_match"
+    type = "text"
+  }
+}
+`
@@ -2,8 +2,11 @@ package circonus
 
 import (
 	"fmt"
+	"testing"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -49,3 +52,128 @@ func checkCheckBundleExists(c *providerContext, checkBundleID api.CIDType) (bool
 
 	return false, nil
 }
+
+func TestCheckTargetsEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"api.example.com", "api.example.com", true},
+		{"API.Example.com", "api.example.com", true},
+		{"api.example.com.", "api.example.com", true},
+		{"api.example.com", "www.example.com", false},
+		{"127.0.0.1", "127.0.0.1", true},
+		{"127.0.0.1", "127.0.0.2", false},
+		{"2001:DB8::1", "2001:db8::1", false},
+	}
+
+	for _, test := range tests {
+		if equivalent := checkTargetsEquivalent(test.a, test.b); equivalent != test.equivalent {
+			t.Errorf("comparing %q and %q: expected %t, got %t", test.a, test.b, test.equivalent, equivalent)
+		}
+	}
+}
+
+func TestCheckCertMetricNames(t *testing.T) {
+	tests := []struct {
+		checkType apiCheckType
+		cfg       map[config.Key]string
+		certs     bool
+	}{
+		{apiCheckTypeHTTPAttr, map[config.Key]string{config.URL: "https://www.example.com/"}, true},
+		{apiCheckTypeHTTPAttr, map[config.Key]string{config.URL: "http://www.example.com/"}, false},
+		{apiCheckTypeTCPAttr, map[config.Key]string{config.UseSSL: "true"}, true},
+		{apiCheckTypeTCPAttr, map[config.Key]string{config.UseSSL: "false"}, false},
+		{apiCheckTypeJSONAttr, map[config.Key]string{config.URL: "https://www.example.com/"}, false},
+	}
+
+	for _, test := range tests {
+		c := newCheck()
+		c.Type = string(test.checkType)
+		for k, v := range test.cfg {
+			c.Config[k] = v
+		}
+
+		names := c.CertMetricNames()
+		if certs := len(names) > 0; certs != test.certs {
+			t.Errorf("%s check %v: expected cert metrics %t, got %q", test.checkType, test.cfg, test.certs, names)
+		}
+	}
+}
+
+func TestCheckMetricLimit(t *testing.T) {
+	tests := []struct {
+		raw      map[string]interface{}
+		expected int
+	}{
+		{map[string]interface{}{}, -1},
+		{map[string]interface{}{checkMetricLimitAttr: 0}, 0},
+		{map[string]interface{}{checkMetricLimitAttr: 25}, 25},
+	}
+
+	for _, test := range tests {
+		raw := map[string]interface{}{
+			checkCollectorAttr:    []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkMetricFilterAttr: []interface{}{map[string]interface{}{"type": "allow", "regex": ".+"}},
+			checkNameAttr:         "statsd",
+			checkStatsdAttr:       []interface{}{map[string]interface{}{checkStatsdSourceIPAttr: "127.0.0.1"}},
+			checkTargetAttr:       "127.0.0.1",
+		}
+		for k, v := range test.raw {
+			raw[k] = v
+		}
+
+		c := newCheck()
+		d := schema.TestResourceDataRaw(t, resourceCheck().Schema, raw)
+		if err := c.ParseConfig(d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if c.MetricLimit == nil || *c.MetricLimit != test.expected {
+			t.Errorf("expected %s %d for %v, got %v", checkMetricLimitAttr, test.expected, test.raw, c.MetricLimit)
+		}
+	}
+}
+
+func TestCheckValidateWithoutNameOrTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+	}{
+		{"http check without name", map[string]interface{}{
+			checkHTTPAttr: []interface{}{map[string]interface{}{checkHTTPURLAttr: "https://api.circonus.com/"}},
+		}},
+		{"cloudwatch check without name or target", map[string]interface{}{
+			checkCloudWatchAttr: []interface{}{map[string]interface{}{
+				checkCloudWatchAPIKeyAttr:    "key",
+				checkCloudWatchAPISecretAttr: "secret",
+				checkCloudWatchMetricAttr:    []interface{}{"CPUUtilization"},
+				checkCloudWatchNamespaceAttr: "AWS/EC2",
+				checkCloudWatchURLAttr:       "https://monitoring.us-east-1.amazonaws.com",
+			}},
+		}},
+		{"dns check without name or target", map[string]interface{}{
+			checkDNSAttr: []interface{}{map[string]interface{}{checkDNSQueryAttr: "example.com"}},
+		}},
+	}
+
+	for _, test := range tests {
+		raw := map[string]interface{}{
+			checkCollectorAttr:    []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkMetricFilterAttr: []interface{}{map[string]interface{}{"type": "allow", "regex": ".+"}},
+		}
+		for k, v := range test.raw {
+			raw[k] = v
+		}
+
+		c := newCheck()
+		d := schema.TestResourceDataRaw(t, resourceCheck().Schema, raw)
+		if err := c.ParseConfig(d); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+}
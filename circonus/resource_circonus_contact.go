@@ -12,6 +12,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -32,6 +33,7 @@ const (
 	contactShortSummaryAttr      = "short_summary"
 	contactSlackAttr             = "slack"
 	contactTagsAttr              = "tags"
+	contactTagsMapAttr           = "tags_map"
 	contactVictorOpsAttr         = "victorops"
 	contactXMPPAttr              = "xmpp"
 
@@ -149,6 +151,7 @@ var contactGroupDescriptions = attrDescrs{
 	contactShortSummaryAttr:         "",
 	contactSlackAttr:                "",
 	contactTagsAttr:                 "",
+	contactTagsMapAttr:              "The tags assigned to the contact group as a map of category to value, instead of tags",
 	contactVictorOpsAttr:            "",
 	contactXMPPAttr:                 "",
 }
@@ -261,7 +264,7 @@ func resourceContactGroup() *schema.Resource {
 							DiffSuppressFunc: suppressEquivalentTimeDurations,
 							StateFunc:        normalizeTimeDurationStringToSeconds,
 							ValidateFunc: validateFuncs(
-								validateDurationMin(contactReminderAttr, "0s"),
+								validateDurationMin(contactReminderAttr, "1s"),
 							),
 						},
 						contactSeverityAttr: {
@@ -321,19 +324,22 @@ func resourceContactGroup() *schema.Resource {
 				},
 			},
 			contactLongMessageAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: suppressWhitespace,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    suppressWhitespace,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			contactLongSubjectAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: suppressWhitespace,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    suppressWhitespace,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			contactLongSummaryAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: suppressWhitespace,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    suppressWhitespace,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			contactNameAttr: {
 				Type:     schema.TypeString,
@@ -368,14 +374,16 @@ func resourceContactGroup() *schema.Resource {
 				},
 			},
 			contactShortMessageAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: suppressWhitespace,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    suppressWhitespace,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			contactShortSummaryAttr: {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: suppressWhitespace,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    suppressWhitespace,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			contactSlackAttr: {
 				Type:     schema.TypeSet,
@@ -434,7 +442,8 @@ func resourceContactGroup() *schema.Resource {
 					}),
 				},
 			},
-			contactTagsAttr: tagMakeConfigSchema(contactTagsAttr),
+			contactTagsAttr:    tagMakeConfigSchema(contactTagsAttr),
+			contactTagsMapAttr: tagMakeMapConfigSchema(contactTagsAttr),
 			contactVictorOpsAttr: {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -523,6 +532,8 @@ func contactGroupCreate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	in.Tags = ctxt.tagsWithDefaults(in.Tags)
+
 	cg, err := ctxt.client.CreateContactGroup(in)
 	if err != nil {
 		return err
@@ -560,6 +571,11 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 
 	cg, err := c.client.FetchContactGroup(api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -615,17 +631,17 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactHTTPAttr, err)
 	}
 
-	_ = d.Set(contactLongMessageAttr, cg.AlertFormats.LongMessage)
-	_ = d.Set(contactLongSubjectAttr, cg.AlertFormats.LongSubject)
-	_ = d.Set(contactLongSummaryAttr, cg.AlertFormats.LongSummary)
+	_ = d.Set(contactLongMessageAttr, contactGroupAlertFormatToState(cg.AlertFormats.LongMessage))
+	_ = d.Set(contactLongSubjectAttr, contactGroupAlertFormatToState(cg.AlertFormats.LongSubject))
+	_ = d.Set(contactLongSummaryAttr, contactGroupAlertFormatToState(cg.AlertFormats.LongSummary))
 	_ = d.Set(contactNameAttr, cg.Name)
 
 	if err := d.Set(contactPagerDutyAttr, pagerDutyState); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactPagerDutyAttr, err)
 	}
 
-	_ = d.Set(contactShortMessageAttr, cg.AlertFormats.ShortMessage)
-	_ = d.Set(contactShortSummaryAttr, cg.AlertFormats.ShortSummary)
+	_ = d.Set(contactShortMessageAttr, contactGroupAlertFormatToState(cg.AlertFormats.ShortMessage))
+	_ = d.Set(contactShortSummaryAttr, contactGroupAlertFormatToState(cg.AlertFormats.ShortSummary))
 
 	if err := d.Set(contactSlackAttr, slackState); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSlackAttr, err)
@@ -635,7 +651,12 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSMSAttr, err)
 	}
 
-	if err := d.Set(contactTagsAttr, cg.Tags); err != nil {
+	tags := c.tagsWithoutDefaults(cg.Tags, tagsFromConfig(d, contactTagsAttr, contactTagsMapAttr))
+	if tagsMapDeclared(d, contactTagsMapAttr) {
+		if err := d.Set(contactTagsMapAttr, tagsToMap(tags)); err != nil {
+			return fmt.Errorf("Unable to store contact %q attribute: %w", contactTagsMapAttr, err)
+		}
+	} else if err := d.Set(contactTagsAttr, tags); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactTagsAttr, err)
 	}
 
@@ -662,6 +683,8 @@ func contactGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	in.Tags = c.tagsWithDefaults(in.Tags)
+
 	in.CID = d.Id()
 
 	if _, err := c.client.UpdateContactGroup(in); err != nil {
@@ -731,6 +754,18 @@ func contactGroupAlertOptionsToState(cg *api.ContactGroup) []interface{} {
 	return alertOptionsList
 }
 
+// contactGroupAlertFormatToState returns an alert format template as it is
+// stored in the state.  The templates are stored verbatim except for leading
+// and trailing whitespace, which the StateFunc of the attributes removes from
+// the configuration as well, e.g. the final newline of a heredoc.
+func contactGroupAlertFormatToState(format *string) string {
+	if format == nil {
+		return ""
+	}
+
+	return suppressWhitespace(*format)
+}
+
 func contactGroupEmailToState(cg *api.ContactGroup) []interface{} {
 	emailContacts := make([]interface{}, 0, len(cg.Contacts.Users)+len(cg.Contacts.External))
 
@@ -834,6 +869,13 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				d, _ := time.ParseDuration(optRaw.(string))
 				cg.Reminders[severityIndex] = uint(d.Seconds())
 			}
+
+			// the API has no representation of an alert option without a
+			// reminder or an escalation, it would be dropped and show up as a
+			// diff on every plan.
+			if cg.Reminders[severityIndex] == 0 && cg.Escalations[severityIndex] == nil {
+				return nil, fmt.Errorf("severity %d %s requires a %s or an escalation", severityIndex+1, contactAlertOptionAttr, contactReminderAttr)
+			}
 		}
 	}
 
@@ -1091,38 +1133,31 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 	}
 
 	if v, ok := d.GetOk(contactLongMessageAttr); ok {
-		msg := v.(string)
+		msg := suppressWhitespace(v)
 		cg.AlertFormats.LongMessage = &msg
 	}
 
 	if v, ok := d.GetOk(contactLongSubjectAttr); ok {
-		msg := v.(string)
+		msg := suppressWhitespace(v)
 		cg.AlertFormats.LongSubject = &msg
 	}
 
 	if v, ok := d.GetOk(contactLongSummaryAttr); ok {
-		msg := v.(string)
+		msg := suppressWhitespace(v)
 		cg.AlertFormats.LongSummary = &msg
 	}
 
 	if v, ok := d.GetOk(contactShortMessageAttr); ok {
-		msg := v.(string)
+		msg := suppressWhitespace(v)
 		cg.AlertFormats.ShortMessage = &msg
 	}
 
 	if v, ok := d.GetOk(contactShortSummaryAttr); ok {
-		msg := v.(string)
+		msg := suppressWhitespace(v)
 		cg.AlertFormats.ShortSummary = &msg
 	}
 
-	if v, ok := d.GetOk(contactShortMessageAttr); ok {
-		msg := v.(string)
-		cg.AlertFormats.ShortMessage = &msg
-	}
-
-	if v, found := d.GetOk(checkTagsAttr); found {
-		cg.Tags = derefStringList(flattenSet(v.(*schema.Set)))
-	}
+	cg.Tags = tagsFromConfig(d, contactTagsAttr, contactTagsMapAttr)
 
 	if cg.AlertFormats.LongMessage == nil && slack {
 		str := `slackformat:
@@ -1269,11 +1304,18 @@ func contactGroupXMPPToState(cg *api.ContactGroup) ([]interface{}, error) {
 // contactGroupAlertOptionsChecksum creates a stable hash of the normalized values
 func contactGroupAlertOptionsChecksum(v interface{}) int {
 	m := v.(map[string]interface{})
+
+	// unset options are missing from the API state and empty in the
+	// configuration, both must hash the same.
+	escalateAfter, _ := m[contactEscalateAfterAttr].(string)
+	escalateTo, _ := m[contactEscalateToAttr].(string)
+	reminder, _ := m[contactReminderAttr].(string)
+
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 	fmt.Fprintf(b, "%x", m[contactSeverityAttr].(int))
-	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(m[contactEscalateAfterAttr]))
-	fmt.Fprint(b, m[contactEscalateToAttr])
-	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(m[contactReminderAttr]))
+	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(escalateAfter))
+	fmt.Fprint(b, escalateTo)
+	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(reminder))
 	return hashcode.String(b.String())
 }
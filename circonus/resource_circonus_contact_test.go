@@ -7,6 +7,7 @@ import (
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -93,6 +94,100 @@ func TestAccCirconusContactGroup_basic(t *testing.T) {
 	})
 }
 
+func TestContactGroupAlertOptionsRoundTrip(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":               "round trip",
+		"aggregation_window": "2m",
+		"alert_option": []interface{}{
+			map[string]interface{}{"severity": 1, "reminder": "5m"},
+			map[string]interface{}{"severity": 2, "reminder": "15m", "escalate_after": "1h", "escalate_to": "/contact_group/4661"},
+			map[string]interface{}{"severity": 3, "escalate_after": "300s", "escalate_to": "/contact_group/4661"},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceContactGroup().Schema, raw)
+	cg, err := getContactGroupInput(d)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if cg.AggregationWindow != 120 {
+		t.Errorf("expected an aggregation window of 120s, got %ds", cg.AggregationWindow)
+	}
+
+	want := make(map[int]bool)
+	for _, opt := range d.Get("alert_option").(*schema.Set).List() {
+		want[contactGroupAlertOptionsChecksum(opt)] = true
+	}
+
+	got := contactGroupAlertOptionsToState(cg)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d alert options, got %d: %v", len(want), len(got), got)
+	}
+	for _, opt := range got {
+		if !want[contactGroupAlertOptionsChecksum(opt)] {
+			t.Errorf("alert option %v does not match the configuration", opt)
+		}
+	}
+
+	raw["alert_option"] = []interface{}{map[string]interface{}{"severity": 4}}
+	d = schema.TestResourceDataRaw(t, resourceContactGroup().Schema, raw)
+	if _, err := getContactGroupInput(d); err == nil || !strings.Contains(err.Error(), "severity 4 alert_option requires a reminder or an escalation") {
+		t.Errorf("expected an error for an empty alert option, got %v", err)
+	}
+}
+
+func TestContactGroupAlertFormatsRoundTrip(t *testing.T) {
+	longMessage := "Check: {name}\nSeverity: {severity}\n%(cleared != null) Cleared: {cleared}%\n"
+	raw := map[string]interface{}{
+		"name":          "alert formats",
+		"long_message":  longMessage,
+		"short_summary": "  {name} is {status}  ",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceContactGroup().Schema, raw)
+	cg, err := getContactGroupInput(d)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tests := []struct {
+		attr   string
+		format *string
+		want   string
+	}{
+		{"long_message", cg.AlertFormats.LongMessage, strings.TrimSpace(longMessage)},
+		{"short_summary", cg.AlertFormats.ShortSummary, "{name} is {status}"},
+	}
+
+	for _, test := range tests {
+		if test.format == nil {
+			t.Errorf("%s: not sent to the API", test.attr)
+			continue
+		}
+		if got := contactGroupAlertFormatToState(test.format); got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.attr, test.want, got)
+		}
+		if *test.format != test.want {
+			t.Errorf("%s: expected %q to be sent to the API, got %q", test.attr, test.want, *test.format)
+		}
+
+		// the API may hand the template back with a trailing newline
+		apiFormat := *test.format + "\n"
+		if got := contactGroupAlertFormatToState(&apiFormat); got != test.want {
+			t.Errorf("%s: expected %q from the API, got %q", test.attr, test.want, got)
+		}
+	}
+
+	if cg.AlertFormats.LongSubject != nil {
+		t.Errorf("expected no long_subject, got %q", *cg.AlertFormats.LongSubject)
+	}
+
+	if _, errs := resourceContactGroup().Schema["long_subject"].ValidateFunc(" \n", "long_subject"); len(errs) == 0 {
+		t.Errorf("expected an error for a blank long_subject")
+	}
+}
+
 func testAccCheckDestroyCirconusContactGroup(s *terraform.State) error {
 	c := testAccProvider.Meta().(*providerContext)
 
@@ -716,6 +716,11 @@ func dashboardRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	dash, err := loadDashboard(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,16 +14,19 @@ import (
 
 const (
 	// circonus_graph.* resource attribute names
+	graphCAQLAttr          = "caql"
 	graphDescriptionAttr   = "description"
 	graphLeftAttr          = "left"
 	graphLineStyleAttr     = "line_style"
 	graphMetricClusterAttr = "metric_cluster"
 	graphNameAttr          = "name"
 	graphNotesAttr         = "notes"
+	graphOverlaySetAttr    = "overlay_set"
 	graphRightAttr         = "right"
 	graphMetricAttr        = "metric"
 	graphStyleAttr         = "graph_style"
 	graphTagsAttr          = "tags"
+	graphTagsMapAttr       = "tags_map"
 	graphGuidesAttr        = "guide"
 
 	// circonus_graph.metric.* resource attribute names
@@ -39,8 +43,21 @@ const (
 	graphMetricHumanNameAttr     = "name"
 	graphMetricMetricTypeAttr    = "metric_type"
 	graphMetricNameAttr          = "metric_name"
+	graphMetricQuantileAttr      = "quantile"
 	graphMetricStackAttr         = "stack"
 
+	// circonus_graph.caql.* resource attribute names
+	graphCAQLActiveAttr        = "active"
+	graphCAQLAxisAttr          = "axis"
+	graphCAQLCheckAttr         = "check"
+	graphCAQLColorAttr         = "color"
+	graphCAQLFormulaAttr       = "formula"
+	graphCAQLFormulaLegendAttr = "legend_formula"
+	graphCAQLHumanNameAttr     = "name"
+	graphCAQLMetricNameAttr    = "metric_name"
+	graphCAQLQueryAttr         = "query"
+	graphCAQLStackAttr         = "stack"
+
 	// circonus_graph.metric_cluster.* resource attribute names
 	graphMetricClusterActiveAttr    = "active"
 	graphMetricClusterAggregateAttr = "aggregate"
@@ -66,18 +83,28 @@ const (
 // 	apiGraphStyleLine = "line"
 // )
 
+const (
+	apiGraphStyleArea = "area"
+
+	// graphStyleStacked is a graph_style preset, see applyStylePreset
+	graphStyleStacked = "stacked"
+)
+
 var graphDescriptions = attrDescrs{
 	// circonus_graph.* resource attribute names
+	graphCAQLAttr:          "A list of CAQL queries to graph",
 	graphDescriptionAttr:   "",
 	graphLeftAttr:          "",
 	graphLineStyleAttr:     "How the line should change between point. A string containing either 'stepped', 'interpolated' or null.",
 	graphNameAttr:          "",
 	graphNotesAttr:         "",
+	graphOverlaySetAttr:    "An existing overlay set attached to the graph, as <graph_cid>/<overlay_set_id>",
 	graphRightAttr:         "",
 	graphMetricAttr:        "",
 	graphMetricClusterAttr: "",
 	graphStyleAttr:         "",
 	graphTagsAttr:          "",
+	graphTagsMapAttr:       "The tags assigned to the graph as a map of category to value, instead of tags",
 	graphGuidesAttr:        "",
 }
 
@@ -96,9 +123,24 @@ var graphMetricDescriptions = attrDescrs{
 	graphMetricMetricTypeAttr:    "",
 	graphMetricHumanNameAttr:     "",
 	graphMetricNameAttr:          "",
+	graphMetricQuantileAttr:      "The quantile (between 0 and 1) of a histogram metric to graph",
 	graphMetricStackAttr:         "",
 }
 
+var graphCAQLDescriptions = attrDescrs{
+	// circonus_graph.caql.* resource attribute names
+	graphCAQLActiveAttr:        "",
+	graphCAQLAxisAttr:          "",
+	graphCAQLCheckAttr:         "The check CID the API assigned to this CAQL datapoint",
+	graphCAQLColorAttr:         "",
+	graphCAQLFormulaAttr:       "",
+	graphCAQLFormulaLegendAttr: "",
+	graphCAQLHumanNameAttr:     "",
+	graphCAQLMetricNameAttr:    "The metric name the API assigned to this CAQL datapoint",
+	graphCAQLQueryAttr:         "The CAQL query used to produce this datapoint",
+	graphCAQLStackAttr:         "",
+}
+
 var graphGuidesDescriptions = attrDescrs{
 	// circonus_graph.metric.* resource attribute names
 	graphGuideHiddenAttr:        "",
@@ -148,6 +190,65 @@ func resourceGraph() *schema.Resource {
 		},
 
 		Schema: convertToHelperSchema(graphDescriptions, map[schemaAttr]*schema.Schema{
+			graphCAQLAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(graphCAQLDescriptions, map[schemaAttr]*schema.Schema{
+						graphCAQLActiveAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						graphCAQLAxisAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "left",
+							ValidateFunc: validateStringIn(graphCAQLAxisAttr, validAxisAttrs),
+						},
+						graphCAQLCheckAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphCAQLColorAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCAQLColorAttr, `^#[0-9a-fA-F]{6}$`),
+						},
+						graphCAQLFormulaAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCAQLFormulaAttr, `^.+$`),
+						},
+						graphCAQLFormulaLegendAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCAQLFormulaLegendAttr, `^.+$`),
+						},
+						graphCAQLHumanNameAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCAQLHumanNameAttr, `.+`),
+						},
+						graphCAQLMetricNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphCAQLQueryAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(graphCAQLQueryAttr, `\S`),
+							StateFunc:    suppressWhitespace,
+						},
+						graphCAQLStackAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCAQLStackAttr, `^[\d]*$`),
+						},
+					}),
+				},
+			},
 			graphDescriptionAttr: {
 				Type:      schema.TypeString,
 				Optional:  true,
@@ -293,6 +394,14 @@ func resourceGraph() *schema.Resource {
 							Optional:     true,
 							ValidateFunc: validateRegexp(graphMetricHumanNameAttr, `.+`),
 						},
+						graphMetricQuantileAttr: {
+							Type:     schema.TypeFloat,
+							Optional: true,
+							ValidateFunc: validateFuncs(
+								validateFloatMin(graphMetricQuantileAttr, 0),
+								validateFloatMax(graphMetricQuantileAttr, 1),
+							),
+						},
 						graphMetricStackAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
@@ -331,7 +440,7 @@ func resourceGraph() *schema.Resource {
 						},
 						graphMetricClusterQueryAttr: {
 							Type:         schema.TypeString,
-							Optional:     true,
+							Required:     true,
 							ValidateFunc: validateRegexp(graphMetricClusterQueryAttr, config.MetricClusterCIDRegex),
 						},
 						graphMetricClusterHumanNameAttr: {
@@ -342,13 +451,19 @@ func resourceGraph() *schema.Resource {
 					}),
 				},
 			},
+			graphOverlaySetAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(graphOverlaySetAttr, overlaySetImportIDRegexp.String()),
+			},
 			graphStyleAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Default:      defaultGraphStyle,
 				ValidateFunc: validateStringIn(graphStyleAttr, validGraphStyles),
 			},
-			graphTagsAttr: tagMakeConfigSchema(graphTagsAttr),
+			graphTagsAttr:    tagMakeConfigSchema(graphTagsAttr),
+			graphTagsMapAttr: tagMakeMapConfigSchema(graphTagsAttr),
 		}),
 	}
 }
@@ -360,6 +475,8 @@ func graphCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing graph schema during create: %w", err)
 	}
 
+	g.Tags = ctxt.tagsWithDefaults(g.Tags)
+
 	if err := g.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating graph: %w", err)
 	}
@@ -397,13 +514,55 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	g, err := loadGraph(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
 	d.SetId(g.CID)
 
+	// CAQL datapoints from the caql block are always appended after the metric
+	// datapoints (see ParseConfig), so the trailing CAQL datapoints are mapped
+	// back to the caql block.  On import there is no caql block in the state and
+	// every datapoint is stored as a metric.
+	var numCAQL int
+	if v, found := d.GetOk(graphCAQLAttr); found {
+		numCAQL = len(v.([]interface{}))
+	}
+	caqlStart := len(g.Datapoints) - numCAQL
+
+	// the stacked preset is sent to the API as an area graph with every
+	// datapoint lacking an explicit stack placed in stack 0, keep those
+	// datapoints without a stack in the state
+	stacked := g.Style != nil && *g.Style == apiGraphStyleArea && d.Get(graphStyleAttr).(string) == graphStyleStacked
+	presetStack := func(path string, stack *uint) bool {
+		if !stacked || stack == nil || *stack != 0 {
+			return false
+		}
+		v, _ := d.Get(path).(string)
+		return v == ""
+	}
+
 	metrics := make([]interface{}, 0, len(g.Datapoints))
-	for _, datapoint := range g.Datapoints {
+	caqls := make([]interface{}, 0, numCAQL)
+	for i, datapoint := range g.Datapoints {
+		if i >= caqlStart && datapoint.CAQL != nil && *datapoint.CAQL != "" {
+			caqlAttrs, err := graphCAQLDatapointToState(datapoint)
+			if err != nil {
+				return err
+			}
+
+			if presetStack(fmt.Sprintf("%s.%d.%s", graphCAQLAttr, len(caqls), graphCAQLStackAttr), datapoint.Stack) {
+				delete(caqlAttrs, string(graphCAQLStackAttr))
+			}
+
+			caqls = append(caqls, caqlAttrs)
+			continue
+		}
+
 		dataPointAttrs := make(map[string]interface{}, 13) // 13 == len(members in api.GraphDatapoint)
 
 		dataPointAttrs[string(graphMetricActiveAttr)] = !datapoint.Hidden
@@ -467,7 +626,11 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 			dataPointAttrs[string(graphMetricHumanNameAttr)] = datapoint.Name
 		}
 
-		if datapoint.Stack != nil {
+		if datapoint.Quantile != nil {
+			dataPointAttrs[string(graphMetricQuantileAttr)] = *datapoint.Quantile
+		}
+
+		if datapoint.Stack != nil && !presetStack(fmt.Sprintf("%s.%d.%s", graphMetricAttr, len(metrics), graphMetricStackAttr), datapoint.Stack) {
 			dataPointAttrs[string(graphMetricStackAttr)] = fmt.Sprintf("%d", *datapoint.Stack)
 		}
 
@@ -513,7 +676,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 			metricClusterAttrs[string(graphMetricHumanNameAttr)] = metricCluster.Name
 		}
 
-		if metricCluster.Stack != nil {
+		if metricCluster.Stack != nil && !presetStack(fmt.Sprintf("%s.%d.%s", graphMetricClusterAttr, len(metricClusters), graphMetricStackAttr), metricCluster.Stack) {
 			metricClusterAttrs[string(graphMetricStackAttr)] = fmt.Sprintf("%d", *metricCluster.Stack)
 		}
 
@@ -564,16 +727,42 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphMetricAttr, err)
 	}
 
+	if err := d.Set(graphCAQLAttr, caqls); err != nil {
+		return fmt.Errorf("Unable to store graph %q attribute: %w", graphCAQLAttr, err)
+	}
+
 	if err := d.Set(graphMetricClusterAttr, metricClusters); err != nil {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphMetricClusterAttr, err)
 	}
 
-	_ = d.Set(graphStyleAttr, g.Style)
+	if stacked {
+		_ = d.Set(graphStyleAttr, graphStyleStacked)
+	} else {
+		_ = d.Set(graphStyleAttr, g.Style)
+	}
 
-	if err := d.Set(graphTagsAttr, tagsToState(apiToTags(g.Tags))); err != nil {
+	tags := ctxt.tagsWithoutDefaults(g.Tags, tagsFromConfig(d, graphTagsAttr, graphTagsMapAttr))
+	if tagsMapDeclared(d, graphTagsMapAttr) {
+		if err := d.Set(graphTagsMapAttr, tagsToMap(tags)); err != nil {
+			return fmt.Errorf("Unable to store graph %q attribute: %w", graphTagsMapAttr, err)
+		}
+	} else if err := d.Set(graphTagsAttr, tagsToState(apiToTags(tags))); err != nil {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphTagsAttr, err)
 	}
 
+	// the overlays of the attached overlay set are not stored, they belong to
+	// the referenced overlay set.  A copy missing from the graph or differing
+	// from its source clears the reference so that it is attached again.
+	if ref, found := d.GetOk(graphOverlaySetAttr); found {
+		attached, err := g.overlaySetAttached(ctxt, ref.(string))
+		if err != nil {
+			return err
+		}
+		if !attached {
+			_ = d.Set(graphOverlaySetAttr, "")
+		}
+	}
+
 	guides := make([]interface{}, 0, len(g.Guides))
 	for _, guide := range g.Guides {
 		guideAttrs := make(map[string]interface{}, 5)
@@ -608,6 +797,13 @@ func graphUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	g.Tags = ctxt.tagsWithDefaults(g.Tags)
+
+	if d.HasChange(graphOverlaySetAttr) {
+		old, _ := d.GetChange(graphOverlaySetAttr)
+		g.previousOverlaySet = old.(string)
+	}
+
 	g.CID = d.Id()
 	if err := g.Update(ctxt); err != nil {
 		return fmt.Errorf("unable to update graph %q: %w", d.Id(), err)
@@ -631,6 +827,11 @@ func graphDelete(d *schema.ResourceData, meta interface{}) error {
 
 type circonusGraph struct {
 	api.Graph
+
+	// overlaySet is the <graph_cid>/<overlay_set_id> reference of the overlay
+	// set attached to the graph, previousOverlaySet the one it replaces
+	overlaySet         string
+	previousOverlaySet string
 }
 
 func newGraph() circonusGraph {
@@ -816,6 +1017,13 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 				}
 			}
 
+			if v, found := metricAttrs[graphMetricQuantileAttr]; found {
+				f := v.(float64)
+				if f != 0 {
+					datapoint.Quantile = &f
+				}
+			}
+
 			if v, found := metricAttrs[graphMetricStackAttr]; found {
 				s := v.(string)
 				if s != "" {
@@ -903,6 +1111,22 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
+	// CAQL datapoints must be appended after the metric datapoints, graphRead
+	// relies on this ordering.
+	if listRaw, found := d.GetOk(graphCAQLAttr); found {
+		caqlList := listRaw.([]interface{})
+		for caqlIdx, caqlListElem := range caqlList {
+			caqlAttrs := newInterfaceMap(caqlListElem.(map[string]interface{}))
+
+			datapoint, err := graphCAQLConfigToDatapoint(caqlAttrs)
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %w", graphCAQLAttr, caqlIdx, err)
+			}
+
+			g.Datapoints = append(g.Datapoints, datapoint)
+		}
+	}
+
 	if listRaw, found := d.GetOk(graphMetricClusterAttr); found {
 		metricClusterList := listRaw.([]interface{})
 
@@ -1011,8 +1235,10 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
-	if v, found := d.GetOk(graphTagsAttr); found {
-		g.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	g.Tags = tagsFromConfig(d, graphTagsAttr, graphTagsMapAttr)
+
+	if v, found := d.GetOk(graphOverlaySetAttr); found {
+		g.overlaySet = v.(string)
 	}
 
 	if listRaw, found := d.GetOk(graphGuidesAttr); found {
@@ -1062,6 +1288,8 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
+	g.applyStylePreset()
+
 	if err := g.Validate(); err != nil {
 		return err
 	}
@@ -1069,7 +1297,141 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
+// graphCAQLConfigToDatapoint converts a single caql block into a CAQL
+// datapoint.  graphCAQLConfigToDatapoint and graphCAQLDatapointToState must be
+// kept in sync.
+func graphCAQLConfigToDatapoint(caqlAttrs interfaceMap) (api.GraphDatapoint, error) {
+	defaultAlpha := "0"
+	datapoint := api.GraphDatapoint{
+		Alpha:      &defaultAlpha,
+		Derive:     false,
+		MetricType: "caql",
+	}
+
+	if v, found := caqlAttrs[graphCAQLActiveAttr]; found {
+		datapoint.Hidden = !(v.(bool))
+	}
+
+	if v, found := caqlAttrs[graphCAQLAxisAttr]; found {
+		switch v.(string) {
+		case "left", "":
+			datapoint.Axis = "l"
+		case "right":
+			datapoint.Axis = "r"
+		default:
+			return datapoint, fmt.Errorf("PROVIDER BUG: Unsupported axis attribute %q: %q", graphCAQLAxisAttr, v.(string))
+		}
+	}
+
+	if v, found := caqlAttrs[graphCAQLColorAttr]; found {
+		s := v.(string)
+		if s != "" {
+			datapoint.Color = &s
+		}
+	}
+
+	if v, found := caqlAttrs[graphCAQLFormulaAttr]; found {
+		s := v.(string)
+		if s != "" {
+			datapoint.DataFormula = &s
+		}
+	}
+
+	if v, found := caqlAttrs[graphCAQLFormulaLegendAttr]; found {
+		s := v.(string)
+		if s != "" {
+			datapoint.LegendFormula = &s
+		}
+	}
+
+	if v, found := caqlAttrs[graphCAQLHumanNameAttr]; found {
+		datapoint.Name = strings.TrimSpace(v.(string))
+	}
+
+	if v, found := caqlAttrs[graphCAQLQueryAttr]; found {
+		s := strings.TrimSpace(v.(string))
+		if s != "" {
+			datapoint.CAQL = &s
+		}
+	}
+
+	if datapoint.CAQL == nil {
+		return datapoint, fmt.Errorf("name=%q: %q can not be empty", datapoint.Name, graphCAQLQueryAttr)
+	}
+
+	if v, found := caqlAttrs[graphCAQLStackAttr]; found {
+		s := v.(string)
+		if s != "" {
+			u64, _ := strconv.ParseUint(s, 10, 64)
+			u := uint(u64)
+			datapoint.Stack = &u
+		}
+	}
+
+	return datapoint, nil
+}
+
+// graphCAQLDatapointToState converts a CAQL datapoint returned by the API into
+// the attributes of a caql block, including the check and metric name the API
+// assigned to the datapoint.
+func graphCAQLDatapointToState(datapoint api.GraphDatapoint) (map[string]interface{}, error) {
+	caqlAttrs := make(map[string]interface{}, 10) // 10 == len(members in circonus_graph.caql)
+
+	caqlAttrs[string(graphCAQLActiveAttr)] = !datapoint.Hidden
+
+	switch datapoint.Axis {
+	case "l", "":
+		caqlAttrs[string(graphCAQLAxisAttr)] = "left"
+	case "r":
+		caqlAttrs[string(graphCAQLAxisAttr)] = "right"
+	default:
+		return nil, fmt.Errorf("PROVIDER BUG: Unsupported axis type %q", datapoint.Axis)
+	}
+
+	if datapoint.CheckID != 0 {
+		caqlAttrs[string(graphCAQLCheckAttr)] = fmt.Sprintf("%s/%d", config.CheckPrefix, datapoint.CheckID)
+	}
+
+	if datapoint.Color != nil {
+		caqlAttrs[string(graphCAQLColorAttr)] = *datapoint.Color
+	}
+
+	if datapoint.DataFormula != nil {
+		caqlAttrs[string(graphCAQLFormulaAttr)] = *datapoint.DataFormula
+	}
+
+	if datapoint.LegendFormula != nil {
+		caqlAttrs[string(graphCAQLFormulaLegendAttr)] = *datapoint.LegendFormula
+	}
+
+	if datapoint.Name != "" {
+		caqlAttrs[string(graphCAQLHumanNameAttr)] = datapoint.Name
+	}
+
+	if datapoint.MetricName != "" {
+		caqlAttrs[string(graphCAQLMetricNameAttr)] = datapoint.MetricName
+	}
+
+	if datapoint.CAQL != nil {
+		caqlAttrs[string(graphCAQLQueryAttr)] = *datapoint.CAQL
+	}
+
+	if datapoint.Stack != nil {
+		caqlAttrs[string(graphCAQLStackAttr)] = fmt.Sprintf("%d", *datapoint.Stack)
+	}
+
+	return caqlAttrs, nil
+}
+
 func (g *circonusGraph) Create(ctxt *providerContext) error {
+	if err := g.validateMetricClusters(ctxt); err != nil {
+		return err
+	}
+
+	if err := g.attachOverlaySet(ctxt); err != nil {
+		return err
+	}
+
 	ng, err := ctxt.client.CreateGraph(&g.Graph)
 	if err != nil {
 		return err
@@ -1081,7 +1443,31 @@ func (g *circonusGraph) Create(ctxt *providerContext) error {
 }
 
 func (g *circonusGraph) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateGraph(&g.Graph)
+	if err := g.validateMetricClusters(ctxt); err != nil {
+		return err
+	}
+
+	// overlay sets are managed by circonus_overlay_set, carry them over so
+	// that updating the graph does not remove them
+	cid := g.CID
+	cur, err := ctxt.client.FetchGraph(api.CIDType(&cid))
+	if err != nil {
+		return fmt.Errorf("Unable to fetch graph %s: %w", g.CID, err)
+	}
+	g.OverlaySets = cur.OverlaySets
+
+	// a replaced overlay set reference detaches the copy of the previous set
+	if g.previousOverlaySet != "" && g.OverlaySets != nil {
+		if graphCID, setID := graphOverlaySetRef(g.previousOverlaySet); graphCID != g.CID {
+			delete(*g.OverlaySets, setID)
+		}
+	}
+
+	if err := g.attachOverlaySet(ctxt); err != nil {
+		return err
+	}
+
+	_, err = ctxt.client.UpdateGraph(&g.Graph)
 	if err != nil {
 		return fmt.Errorf("Unable to update graph %s: %w", g.CID, err)
 	}
@@ -1089,6 +1475,131 @@ func (g *circonusGraph) Update(ctxt *providerContext) error {
 	return nil
 }
 
+// applyStylePreset translates the graph_style presets the API does not know
+// about.  The stacked preset renders as an area graph and places every
+// datapoint and metric cluster without an explicit stack into stack 0, an
+// explicit stack on a datapoint takes precedence.
+func (g *circonusGraph) applyStylePreset() {
+	if g.Style == nil || *g.Style != graphStyleStacked {
+		return
+	}
+
+	area := apiGraphStyleArea
+	g.Style = &area
+
+	for i := range g.Datapoints {
+		if g.Datapoints[i].Stack == nil {
+			var stack uint
+			g.Datapoints[i].Stack = &stack
+		}
+	}
+
+	for i := range g.MetricClusters {
+		if g.MetricClusters[i].Stack == nil {
+			var stack uint
+			g.MetricClusters[i].Stack = &stack
+		}
+	}
+}
+
+// attachOverlaySet copies the referenced overlay set into the overlay sets of
+// the graph, under the ID of the overlay set.  The API has no overlay sets of
+// their own, an overlay set belongs to a graph, so the referenced set must
+// exist on another graph.
+func (g *circonusGraph) attachOverlaySet(ctxt *providerContext) error {
+	if g.overlaySet == "" {
+		return nil
+	}
+
+	graphCID, setID := graphOverlaySetRef(g.overlaySet)
+	if g.CID != "" && graphCID == g.CID {
+		return fmt.Errorf("%s %q must reference an overlay set of another graph", graphOverlaySetAttr, g.overlaySet)
+	}
+
+	set, found, err := fetchGraphOverlaySet(ctxt, g.overlaySet)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s %q does not exist", graphOverlaySetAttr, g.overlaySet)
+	}
+
+	if g.OverlaySets == nil {
+		sets := make(map[string]api.GraphOverlaySet, 1)
+		g.OverlaySets = &sets
+	}
+	(*g.OverlaySets)[setID] = set
+
+	return nil
+}
+
+// overlaySetAttached returns true if the graph carries an up to date copy of
+// the referenced overlay set.  A referenced set which no longer exists is not
+// attached, the error is reported when the graph is updated.
+func (g *circonusGraph) overlaySetAttached(ctxt *providerContext, ref string) (bool, error) {
+	_, setID := graphOverlaySetRef(ref)
+	if g.OverlaySets == nil {
+		return false, nil
+	}
+	attached, ok := (*g.OverlaySets)[setID]
+	if !ok {
+		return false, nil
+	}
+
+	set, found, err := fetchGraphOverlaySet(ctxt, ref)
+	if err != nil {
+		return false, err
+	}
+
+	return found && reflect.DeepEqual(attached, set), nil
+}
+
+// fetchGraphOverlaySet returns the overlay set referenced as
+// <graph_cid>/<overlay_set_id> and whether it exists.
+func fetchGraphOverlaySet(ctxt *providerContext, ref string) (api.GraphOverlaySet, bool, error) {
+	graphCID, setID := graphOverlaySetRef(ref)
+	sg, err := ctxt.client.FetchGraph(api.CIDType(&graphCID))
+	if err != nil {
+		if api.IsNotFound(err) {
+			return api.GraphOverlaySet{}, false, nil
+		}
+		return api.GraphOverlaySet{}, false, fmt.Errorf("unable to fetch %s %q: %w", graphOverlaySetAttr, ref, err)
+	}
+
+	if sg.OverlaySets == nil {
+		return api.GraphOverlaySet{}, false, nil
+	}
+	set, ok := (*sg.OverlaySets)[setID]
+
+	return set, ok, nil
+}
+
+// graphOverlaySetRef splits an overlay set reference into the CID of its graph
+// and the ID of the overlay set.
+func graphOverlaySetRef(ref string) (graphCID, setID string) {
+	m := overlaySetImportIDRegexp.FindStringSubmatch(ref)
+	if m == nil {
+		return "", ""
+	}
+	return m[1], m[2]
+}
+
+// validateMetricClusters verifies that the metric clusters referenced by the
+// graph exist, the API accepts unknown clusters and renders nothing for them.
+func (g *circonusGraph) validateMetricClusters(ctxt *providerContext) error {
+	for i, mc := range g.MetricClusters {
+		cid := mc.MetricCluster
+		if _, err := ctxt.client.FetchMetricCluster(api.CIDType(&cid), ""); err != nil {
+			if api.IsNotFound(err) {
+				return fmt.Errorf("Error with %s[%d] name=%q: %s %q does not exist", graphMetricClusterAttr, i, mc.Name, graphMetricClusterQueryAttr, cid)
+			}
+			return fmt.Errorf("Error with %s[%d] name=%q: unable to fetch %s %q: %w", graphMetricClusterAttr, i, mc.Name, graphMetricClusterQueryAttr, cid, err)
+		}
+	}
+
+	return nil
+}
+
 func (g *circonusGraph) Validate() error {
 	for i, datapoint := range g.Datapoints {
 		// if *g.Style == apiGraphStyleLine && datapoint.Alpha != nil && *datapoint.Alpha != "0" {
@@ -1111,6 +1622,10 @@ func (g *circonusGraph) Validate() error {
 		// 	return fmt.Errorf("Error with %s[%d] name=%q: %q attribute is mutually exclusive with attributes %s or %s or %s", graphMetricAttr, i, datapoint.Name, graphMetricSearchAttr, graphMetricNameAttr, graphMetricCheckAttr, graphMetricCAQLAttr)
 		// }
 
+		if datapoint.Quantile != nil && datapoint.MetricType != "histogram" {
+			return fmt.Errorf("Error with %s[%d] (name=%q): attribute %q is only supported when %s=%q", graphMetricAttr, i, datapoint.Name, graphMetricQuantileAttr, graphMetricMetricTypeAttr, "histogram")
+		}
+
 		if datapoint.MetricType == "text" && datapoint.Derive != nil {
 			v := datapoint.Derive
 			switch v.(type) {
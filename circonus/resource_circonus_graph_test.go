@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -130,6 +131,163 @@ func TestAccCirconusGraph_basic(t *testing.T) {
 	})
 }
 
+func TestValidateGraphAxisOptions(t *testing.T) {
+	tests := []struct {
+		opts map[string]interface{}
+		err  string
+	}{
+		{map[string]interface{}{"logarithmic": "10", "min": "-1", "max": "20"}, ""},
+		{map[string]interface{}{"max": "11"}, ""},
+		{map[string]interface{}{"min": "5", "max": "5"}, "must be less than"},
+		{map[string]interface{}{"min": "10", "max": "1"}, "must be less than"},
+		{map[string]interface{}{"min": "low"}, "must be a number"},
+		{map[string]interface{}{"logarithmic": "yes"}, "must be 0 or a positive integer"},
+		{map[string]interface{}{"base": "2"}, "Invalid axis option"},
+	}
+
+	for _, test := range tests {
+		_, errs := validateGraphAxisOptions(test.opts, "left")
+		switch {
+		case test.err == "" && len(errs) > 0:
+			t.Errorf("%v: unexpected errors %v", test.opts, errs)
+		case test.err != "" && len(errs) == 0:
+			t.Errorf("%v: expected error containing %q", test.opts, test.err)
+		case test.err != "" && !strings.Contains(errs[0].Error(), test.err):
+			t.Errorf("%v: expected error containing %q, got %v", test.opts, test.err, errs)
+		}
+	}
+}
+
+func TestGraphApplyStylePreset(t *testing.T) {
+	stacked := graphStyleStacked
+	explicit := uint(2)
+	g := circonusGraph{}
+	g.Style = &stacked
+	g.Datapoints = []api.GraphDatapoint{{Name: "default"}, {Name: "explicit", Stack: &explicit}}
+	g.MetricClusters = []api.GraphMetricCluster{{Name: "cluster"}}
+
+	g.applyStylePreset()
+
+	if g.Style == nil || *g.Style != apiGraphStyleArea {
+		t.Fatalf("expected style %q, got %v", apiGraphStyleArea, g.Style)
+	}
+	if g.Datapoints[0].Stack == nil || *g.Datapoints[0].Stack != 0 {
+		t.Errorf("expected datapoint without stack to be placed in stack 0, got %v", g.Datapoints[0].Stack)
+	}
+	if g.Datapoints[1].Stack == nil || *g.Datapoints[1].Stack != explicit {
+		t.Errorf("expected explicit stack %d to be kept, got %v", explicit, g.Datapoints[1].Stack)
+	}
+	if g.MetricClusters[0].Stack == nil || *g.MetricClusters[0].Stack != 0 {
+		t.Errorf("expected metric cluster without stack to be placed in stack 0, got %v", g.MetricClusters[0].Stack)
+	}
+
+	line := "line"
+	g = circonusGraph{}
+	g.Style = &line
+	g.Datapoints = []api.GraphDatapoint{{Name: "default"}}
+	g.applyStylePreset()
+	if *g.Style != line || g.Datapoints[0].Stack != nil {
+		t.Errorf("expected line graph to be unchanged, got style %q stack %v", *g.Style, g.Datapoints[0].Stack)
+	}
+}
+
+func TestAccCirconusGraph_caql(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusGraph,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusGraphCAQLConfigFmt, checkName, graphName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "name", graphName),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "metric.0.metric_name", "maximum"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "metric.0.name", "Maximum Latency"),
+
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.#", "2"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.0.query", `find("maximum") | stats:max()`),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.0.name", "Max of Maximum"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.0.axis", "left"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.0.color", "#657aa6"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.0.active", "true"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.1.query", `find("minimum") | stats:min()`),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.1.name", "Min of Minimum"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.1.axis", "right"),
+					resource.TestCheckResourceAttr("circonus_graph.caql-points", "caql.1.stack", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCirconusGraph_metricCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusGraph,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusGraphMetricClusterConfigFmt, checkName, graphName, "circonus_metric_cluster.maximum.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_graph.cluster-points", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_graph.cluster-points", "metric_cluster.#", "1"),
+					resource.TestCheckResourceAttr("circonus_graph.cluster-points", "metric_cluster.0.aggregate", "mean"),
+					resource.TestCheckResourceAttr("circonus_graph.cluster-points", "metric_cluster.0.name", "Average of Maximum"),
+					resource.TestCheckResourceAttrPair("circonus_graph.cluster-points", "metric_cluster.0.query", "circonus_metric_cluster.maximum", "id"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusGraphMetricClusterConfigFmt, checkName, graphName, `"/metric_cluster/0"`),
+				ExpectError: regexp.MustCompile(`does not exist`),
+			},
+		},
+	})
+}
+
+func TestAccCirconusGraph_overlaySet(t *testing.T) {
+	overlayGraphName := fmt.Sprintf("Test Graph - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusGraph,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusOverlaySetConfigFmt, checkName, overlayGraphName) +
+					fmt.Sprintf(testAccCirconusGraphOverlaySetConfigFmt, graphName, `"${circonus_graph.overlay-graph.id}/${circonus_overlay_set.trends.id}"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("circonus_graph.shared-overlays", "overlay_set", regexp.MustCompile(`^/graph/.+/[^/]+$`)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusOverlaySetConfigFmt, checkName, overlayGraphName) +
+					fmt.Sprintf(testAccCirconusGraphOverlaySetConfigFmt, graphName, `"${circonus_graph.overlay-graph.id}/missing"`),
+				ExpectError: regexp.MustCompile(`does not exist`),
+			},
+		},
+	})
+}
+
+func TestGraphOverlaySetRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		graphCID string
+		setID    string
+	}{
+		{"/graph/1234/abcdef", "/graph/1234", "abcdef"},
+		{"/graph/bd72aabc-90b9-4039-cc30-c9ab838c18f5/zshift", "/graph/bd72aabc-90b9-4039-cc30-c9ab838c18f5", "zshift"},
+		{"abcdef", "", ""},
+	}
+
+	for _, test := range tests {
+		graphCID, setID := graphOverlaySetRef(test.ref)
+		if graphCID != test.graphCID || setID != test.setID {
+			t.Errorf("%q: expected (%q, %q), got (%q, %q)", test.ref, test.graphCID, test.setID, graphCID, setID)
+		}
+	}
+}
+
 func testAccCheckDestroyCirconusGraph(s *terraform.State) error {
 	ctxt := testAccProvider.Meta().(*providerContext)
 
@@ -260,3 +418,125 @@ EOF
   tags = "${var.test_tags}"
 }
 `
+
+const testAccCirconusGraphCAQLConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  metric {
+    name = "minimum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_graph" "caql-points" {
+  name = "%s"
+  description = "Terraform Test: caql graph"
+  graph_style = "line"
+
+  metric {
+    check = "${circonus_check.api_latency.checks[0]}"
+    metric_name = "maximum"
+    metric_type = "numeric"
+    name = "Maximum Latency"
+  }
+
+  caql {
+    query = "find(\"maximum\") | stats:max()"
+    name = "Max of Maximum"
+    color = "#657aa6"
+  }
+
+  caql {
+    query = "find(\"minimum\") | stats:min()"
+    name = "Min of Minimum"
+    axis = "right"
+    stack = "0"
+  }
+}
+`
+
+const testAccCirconusGraphMetricClusterConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_metric_cluster" "maximum" {
+  name = "maximum"
+
+  query {
+    definition = "maximum"
+    type = "average"
+  }
+}
+
+resource "circonus_graph" "cluster-points" {
+  name = "%s"
+  description = "Terraform Test: metric cluster graph"
+  graph_style = "line"
+
+  metric {
+    check = "${circonus_check.api_latency.checks[0]}"
+    metric_name = "maximum"
+    metric_type = "numeric"
+    name = "Maximum Latency"
+  }
+
+  metric_cluster {
+    query = %s
+    aggregate = "mean"
+    color = "#657aa6"
+    name = "Average of Maximum"
+  }
+}
+`
+
+const testAccCirconusGraphOverlaySetConfigFmt = `
+resource "circonus_graph" "shared-overlays" {
+  name = "%s"
+  graph_style = "line"
+  overlay_set = %s
+
+  metric {
+    check = "${circonus_check.api_latency.checks[0]}"
+    metric_name = "maximum"
+    metric_type = "numeric"
+    name = "Maximum Latency"
+    axis = "left"
+  }
+}
+`
@@ -1,57 +1,84 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// maintenanceImportItemPrefix is the prefix of import IDs naming the item a
+// maintenance window covers rather than the window itself
+const maintenanceImportItemPrefix = "item="
+
 func resourceMaintenance() *schema.Resource {
 	return &schema.Resource{
-		Create: maintenanceCreate,
-		Read:   maintenanceRead,
-		Update: maintenanceUpdate,
-		Delete: maintenanceDelete,
-		Exists: maintenanceExists,
+		Create:        maintenanceCreate,
+		Read:          maintenanceRead,
+		Update:        maintenanceUpdate,
+		Delete:        maintenanceDelete,
+		Exists:        maintenanceExists,
+		CustomizeDiff: maintenanceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: importStatePassthroughUnescape,
+			State: maintenanceImport,
 		},
 
 		Schema: map[string]*schema.Schema{
 			"account": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"check", "rule_set", "target"},
+				ConflictsWith: []string{"check", "rule_set", "target", "type", "item"},
 			},
 			"check": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"account", "rule_set", "target"},
+				ConflictsWith: []string{"account", "rule_set", "target", "type", "item"},
 			},
 			"rule_set": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"check", "account", "target"},
+				ConflictsWith: []string{"check", "account", "target", "type", "item"},
 			},
 			"target": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"check", "rule_set", "account"},
+				ConflictsWith: []string{"check", "rule_set", "account", "type", "item"},
+			},
+			"type": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"account", "check", "rule_set", "target"},
+				Description:   "The scope of the maintenance window: account, check, host, rule_set, or set",
+				ValidateFunc:  validateStringIn("type", validMaintenanceTypes),
+			},
+			"item": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"account", "check", "rule_set", "target"},
+				Description:   "The item of the scope put into maintenance, not used for account-wide maintenance",
 			},
 			"notes": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 			"severities": {
-				Type:     schema.TypeList,
-				Required: true,
-				MinItems: 1,
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    len(validMaintenanceSeverities),
+				Description: `The severities ("1"-"5") put into maintenance, an empty or omitted list means all severities`,
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
-					ValidateFunc: validateRegexp(ruleSetContainsAttr, `[1-5]`),
+					ValidateFunc: validateStringIn("severities", validMaintenanceSeverities),
 				},
 			},
 			"start": {
@@ -71,6 +98,14 @@ func resourceMaintenance() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"last_modified": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_modified_by": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -83,6 +118,8 @@ func maintenanceCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing maintenance schema during create: %w", err)
 	}
 
+	m.Tags = ctxt.tagsWithDefaults(m.Tags)
+
 	if err := m.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating maintenance: %w", err)
 	}
@@ -114,11 +151,35 @@ func maintenanceRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	m, err := loadMaintenance(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
+	// outside of an update by terraform, a changed modification time means the
+	// window was changed out-of-band since the last read
+	changing := d.HasChanges("account", "check", "rule_set", "target", "notes", "severities", "start", "stop", "tags")
+	if lastModified, ok := d.GetOk("last_modified"); ok && !changing && uint(lastModified.(int)) != m.LastModified {
+		log.Printf("[DEBUG] maintenance window %s was modified by %s outside of terraform", m.CID, m.LastModifiedBy)
+	}
+
+	// the scope is stored in the attributes it was configured with, imported
+	// windows use the item specific attributes
+	legacyScope := d.Get("type").(string) == "" || maintenanceLegacyScope(d)
+
 	d.SetId(m.CID)
-	if m.Type == "account" {
+	_ = d.Set("last_modified", int(m.LastModified))
+	_ = d.Set("last_modified_by", m.LastModifiedBy)
+	_ = d.Set("type", m.Type)
+	if !legacyScope {
+		// account-wide maintenance always covers the current account
+		if m.Type != "account" {
+			_ = d.Set("item", m.Item)
+		}
+	} else if m.Type == "account" {
 		_ = d.Set("account", m.Item)
 	} else if m.Type == "rule_set" {
 		_ = d.Set("rule_set", m.Item)
@@ -129,15 +190,18 @@ func maintenanceRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	_ = d.Set("notes", m.Notes)
-	_ = d.Set("severities", m.Severities.([]interface{}))
-	start := time.Unix(int64(m.Start), 0)
-	stop := time.Unix(int64(m.Stop), 0)
-
-	_ = d.Set("start", start.Format(time.RFC3339))
-	_ = d.Set("stop", stop.Format(time.RFC3339))
+	// a window covering all severities is stored as an empty list unless the
+	// severities were listed explicitly
+	severities := maintenanceAPISeverities(m.Severities)
+	if len(severities) == len(validMaintenanceSeverities) && len(d.Get("severities").([]interface{})) == 0 {
+		severities = []string{}
+	}
+	_ = d.Set("severities", severities)
+	_ = d.Set("start", m.StartTime().Format(time.RFC3339))
+	_ = d.Set("stop", m.StopTime().Format(time.RFC3339))
 	tags := make([]interface{}, 0)
 	if len(m.Tags) > 0 {
-		for _, t := range m.Tags {
+		for _, t := range ctxt.tagsWithoutDefaults(m.Tags, derefStringList(flattenList(d.Get("tags").([]interface{})))) {
 			tags = append(tags, t)
 		}
 	}
@@ -145,6 +209,73 @@ func maintenanceRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// maintenanceImport imports a maintenance window by CID, or by the item it
+// covers when the ID is of the form item=/check/123
+func maintenanceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, _ := url.PathUnescape(d.Id())
+	if !strings.HasPrefix(id, maintenanceImportItemPrefix) {
+		d.SetId(id)
+		return []*schema.ResourceData{d}, nil
+	}
+
+	ctxt := meta.(*providerContext)
+	item := strings.TrimPrefix(id, maintenanceImportItemPrefix)
+	windows, err := ctxt.client.FetchMaintenanceWindowsByItem(item)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search maintenance windows of %q: %w", item, err)
+	}
+
+	m, err := maintenanceSelectWindow(*windows, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("unable to import maintenance window of %q: %w", item, err)
+	}
+
+	d.SetId(m.CID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// maintenanceSelectWindow returns the window active at now, or failing that
+// the window with the latest start.  More than one candidate is an error
+// since the import would be a guess.
+func maintenanceSelectWindow(windows []api.Maintenance, now time.Time) (*api.Maintenance, error) {
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no maintenance window found")
+	}
+
+	ts := uint(now.Unix())
+	var active []api.Maintenance
+	for _, w := range windows {
+		if w.Start <= ts && ts <= w.Stop {
+			active = append(active, w)
+		}
+	}
+
+	candidates := active
+	if len(candidates) == 0 {
+		var latest uint
+		for _, w := range windows {
+			switch {
+			case len(candidates) == 0 || w.Start > latest:
+				latest = w.Start
+				candidates = []api.Maintenance{w}
+			case w.Start == latest:
+				candidates = append(candidates, w)
+			}
+		}
+	}
+
+	if len(candidates) > 1 {
+		cids := make([]string, 0, len(candidates))
+		for _, w := range candidates {
+			cids = append(cids, w.CID)
+		}
+		return nil, fmt.Errorf("multiple maintenance windows match (%s), import one by CID instead", strings.Join(cids, ", "))
+	}
+
+	return &candidates[0], nil
+}
+
 func maintenanceUpdate(d *schema.ResourceData, meta interface{}) error {
 	ctxt := meta.(*providerContext)
 	m := newMaintenance()
@@ -153,6 +284,8 @@ func maintenanceUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	m.Tags = ctxt.tagsWithDefaults(m.Tags)
+
 	m.CID = d.Id()
 
 	if err := m.Update(ctxt); err != nil {
@@ -200,6 +333,11 @@ func loadMaintenance(ctxt *providerContext, cid api.CIDType) (circonusMaintenanc
 
 func (m *circonusMaintenance) ParseConfig(d *schema.ResourceData) error {
 
+	if v, found := d.GetOk("type"); found && v.(string) != "" {
+		m.Type = v.(string)
+		m.Item = d.Get("item").(string)
+	}
+
 	if v, found := d.GetOk("account"); found && v.(string) != "" {
 		m.Item = v.(string)
 		m.Type = "account"
@@ -224,29 +362,39 @@ func (m *circonusMaintenance) ParseConfig(d *schema.ResourceData) error {
 		m.Notes = v.(string)
 	}
 
+	severities := make([]string, 0, len(validMaintenanceSeverities))
 	if v, found := d.GetOk("severities"); found && len(v.([]interface{})) > 0 {
-		m.Severities = make([]string, 0)
+		seen := make(map[string]bool, len(validMaintenanceSeverities))
 		for _, s := range v.([]interface{}) {
-			m.Severities = append(m.Severities.([]string), s.(string))
+			if seen[s.(string)] {
+				return fmt.Errorf("duplicate severity %q", s.(string))
+			}
+			seen[s.(string)] = true
+			severities = append(severities, s.(string))
+		}
+	} else {
+		for _, s := range validMaintenanceSeverities {
+			severities = append(severities, string(s))
 		}
 	}
+	m.Severities = severities
 
 	if v, found := d.GetOk("start"); found && v.(string) != "" {
 		t, err := time.Parse(time.RFC3339, v.(string))
 		if err == nil {
-			m.Start = uint(t.Unix())
+			m.SetStartTime(t)
 		}
 	}
 
 	if v, found := d.GetOk("stop"); found && v.(string) != "" {
 		t, err := time.Parse(time.RFC3339, v.(string))
 		if err == nil {
-			m.Stop = uint(t.Unix())
+			m.SetStopTime(t)
 		}
 	}
 
-	if v, found := d.GetOk("tags"); found && len(v.([]string)) > 0 {
-		m.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	if v, found := d.GetOk("tags"); found {
+		m.Tags = derefStringList(flattenList(v.([]interface{})))
 	}
 
 	if err := m.Validate(); err != nil {
@@ -257,6 +405,10 @@ func (m *circonusMaintenance) ParseConfig(d *schema.ResourceData) error {
 }
 
 func (m *circonusMaintenance) Create(ctxt *providerContext) error {
+	if err := m.resolveItem(ctxt); err != nil {
+		return err
+	}
+
 	cm, err := ctxt.client.CreateMaintenanceWindow(&m.Maintenance)
 	if err != nil {
 		return err
@@ -268,6 +420,10 @@ func (m *circonusMaintenance) Create(ctxt *providerContext) error {
 }
 
 func (m *circonusMaintenance) Update(ctxt *providerContext) error {
+	if err := m.resolveItem(ctxt); err != nil {
+		return err
+	}
+
 	_, err := ctxt.client.UpdateMaintenanceWindow(&m.Maintenance)
 	if err != nil {
 		return fmt.Errorf("Unable to update maintenance %s: %w", m.CID, err)
@@ -276,6 +432,103 @@ func (m *circonusMaintenance) Update(ctxt *providerContext) error {
 	return nil
 }
 
+// resolveItem sets the item of account-wide maintenance to the current
+// account
+func (m *circonusMaintenance) resolveItem(ctxt *providerContext) error {
+	if m.Type != "account" || m.Item != "" {
+		return nil
+	}
+
+	cid := ""
+	a, err := ctxt.client.FetchAccount(api.CIDType(&cid))
+	if err != nil {
+		return fmt.Errorf("unable to fetch the current account: %w", err)
+	}
+	m.Item = a.CID
+
+	return nil
+}
+
 func (m *circonusMaintenance) Validate() error {
 	return nil
 }
+
+// maintenanceLegacyScope returns true if the scope of the window is set with
+// one of the item specific attributes rather than type and item
+func maintenanceLegacyScope(d interface{ Get(string) interface{} }) bool {
+	for _, attr := range []string{"account", "check", "rule_set", "target"} {
+		if d.Get(attr).(string) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceCustomizeDiff rejects type and item combinations the API would
+// reject at plan time
+func maintenanceCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if maintenanceLegacyScope(d) || !d.NewValueKnown("type") || !d.NewValueKnown("item") {
+		return nil
+	}
+
+	return maintenanceValidateScope(d.Get("type").(string), d.Get("item").(string))
+}
+
+// maintenanceValidateScope verifies that item is consistent with the type of
+// a maintenance window
+func maintenanceValidateScope(typ, item string) error {
+	switch typ {
+	case "":
+		if item != "" {
+			return fmt.Errorf("item %q requires a type", item)
+		}
+	case "account":
+		if item != "" {
+			return fmt.Errorf("account-wide maintenance does not take an item, got %q", item)
+		}
+	case "check":
+		if !regexp.MustCompile(config.CheckCIDRegex).MatchString(item) {
+			return fmt.Errorf("check maintenance requires a check CID item, got %q", item)
+		}
+	case "rule_set":
+		if !regexp.MustCompile(config.RuleSetCIDRegex).MatchString(item) {
+			return fmt.Errorf("rule_set maintenance requires a rule set CID item, got %q", item)
+		}
+	case "host":
+		if item == "" || strings.HasPrefix(item, "/") {
+			return fmt.Errorf("host maintenance requires a host name item, got %q", item)
+		}
+	case "set":
+		if item == "" {
+			return fmt.Errorf("set maintenance requires an item")
+		}
+	}
+
+	return nil
+}
+
+// maintenanceAPISeverities normalizes the severities returned by the API, a
+// list of strings or numbers, or a CSV string, into a list of strings
+func maintenanceAPISeverities(v interface{}) []string {
+	severities := make([]string, 0, len(validMaintenanceSeverities))
+	switch u := v.(type) {
+	case string:
+		for _, s := range strings.Split(u, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				severities = append(severities, s)
+			}
+		}
+	case []string:
+		severities = append(severities, u...)
+	case []interface{}:
+		for _, s := range u {
+			switch w := s.(type) {
+			case string:
+				severities = append(severities, w)
+			case float64:
+				severities = append(severities, strconv.Itoa(int(w)))
+			}
+		}
+	}
+	return severities
+}
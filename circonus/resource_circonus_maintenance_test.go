@@ -2,6 +2,8 @@ package circonus
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -40,10 +42,104 @@ func TestAccCirconusMaintenance_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_maintenance.check-maintenance", "severities.4", "5"),
 				),
 			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusMaintenanceDuplicateSeverityConfigFmt, checkName, startTime, stopTime),
+				ExpectError: regexp.MustCompile(`duplicate severity "1"`),
+			},
 		},
 	})
 }
 
+func TestMaintenanceSelectWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	past := api.Maintenance{CID: "/maintenance/1", Start: 100, Stop: 200}
+	active := api.Maintenance{CID: "/maintenance/2", Start: 900, Stop: 1100}
+	future := api.Maintenance{CID: "/maintenance/3", Start: 2000, Stop: 3000}
+
+	tests := []struct {
+		windows []api.Maintenance
+		cid     string
+		err     string
+	}{
+		{nil, "", "no maintenance window found"},
+		{[]api.Maintenance{past, active, future}, active.CID, ""},
+		{[]api.Maintenance{past, future}, future.CID, ""},
+		{[]api.Maintenance{past}, past.CID, ""},
+		{[]api.Maintenance{active, {CID: "/maintenance/4", Start: 950, Stop: 1050}}, "", "multiple maintenance windows match"},
+		{[]api.Maintenance{future, {CID: "/maintenance/5", Start: 2000, Stop: 2500}}, "", "multiple maintenance windows match"},
+	}
+
+	for i, test := range tests {
+		m, err := maintenanceSelectWindow(test.windows, now)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%d: expected error %q, got %v", i, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+			continue
+		}
+		if m.CID != test.cid {
+			t.Errorf("%d: expected %q, got %q", i, test.cid, m.CID)
+		}
+	}
+}
+
+func TestMaintenanceAPISeverities(t *testing.T) {
+	tests := []struct {
+		severities interface{}
+		expected   []string
+	}{
+		{nil, []string{}},
+		{"1,2, 3", []string{"1", "2", "3"}},
+		{[]string{"1"}, []string{"1"}},
+		{[]interface{}{"1", "5"}, []string{"1", "5"}},
+		{[]interface{}{float64(2), float64(4)}, []string{"2", "4"}},
+	}
+
+	for _, test := range tests {
+		severities := maintenanceAPISeverities(test.severities)
+		if !reflect.DeepEqual(severities, test.expected) {
+			t.Errorf("normalizing %#v: expected %q, got %q", test.severities, test.expected, severities)
+		}
+	}
+}
+
+func TestMaintenanceValidateScope(t *testing.T) {
+	tests := []struct {
+		typ   string
+		item  string
+		valid bool
+	}{
+		{"", "", true},
+		{"", "/check/123", false},
+		{"account", "", true},
+		{"account", "/account/123", false},
+		{"check", "/check/123", true},
+		{"check", "/rule_set/123_foo", false},
+		{"check", "", false},
+		{"rule_set", "/rule_set/123_foo", true},
+		{"rule_set", "/check/123", false},
+		{"host", "app1.example.org", true},
+		{"host", "/check/123", false},
+		{"host", "", false},
+		{"set", "", false},
+		{"set", "web", true},
+	}
+
+	for _, test := range tests {
+		err := maintenanceValidateScope(test.typ, test.item)
+		if test.valid && err != nil {
+			t.Errorf("type %q item %q: unexpected error: %v", test.typ, test.item, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("type %q item %q: expected an error", test.typ, test.item)
+		}
+	}
+}
+
 func testAccCheckDestroyCirconusMaintenance(s *terraform.State) error {
 	ctxt := testAccProvider.Meta().(*providerContext)
 
@@ -115,3 +211,34 @@ resource "circonus_maintenance" "check-maintenance" {
 }
 
 `
+
+const testAccCirconusMaintenanceDuplicateSeverityConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_maintenance" "check-maintenance" {
+  check = circonus_check.api_latency.check_id
+  start = "%s"
+  stop = "%s"
+  notes = "foo notes"
+  severities = ["1", "1"]
+}
+`
@@ -4,11 +4,16 @@ package circonus
 // actually exist within Circonus.  The `circonus_check` resource uses
 // `circonus_metric` as input to its `metric` attribute.  The `circonus_check`
 // resource can, if configured, override various parameters in the
-// `circonus_metric` resource if no value was set.
+// `circonus_metric` resource if no value was set.  Existing metrics can be
+// imported by their metric CID.
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,7 +22,9 @@ const (
 	metricActiveAttr = "active"
 	metricIDAttr     = "id"
 	metricNameAttr   = "name"
+	metricTagsAttr   = "tags"
 	metricTypeAttr   = "type"
+	metricUnitsAttr  = "units"
 
 	// CheckBundle.Metric.Status can be one of these values
 	metricStatusActive    = "active"
@@ -27,9 +34,14 @@ const (
 var metricDescriptions = attrDescrs{
 	metricActiveAttr: "Enables or disables the metric",
 	metricNameAttr:   "Name of the metric",
+	metricTagsAttr:   "Stream tags assigned to the metric (category:value)",
 	metricTypeAttr:   "Type of metric (e.g. numeric, histogram, text)",
+	metricUnitsAttr:  "The unit of measurement of the metric (e.g. bytes, ms)",
 }
 
+// metricUnitsRegexp rejects whitespace, quotes and backslashes in units
+const metricUnitsRegexp = "^[^\\s\"'`\\\\]+$"
+
 func resourceMetric() *schema.Resource {
 	return &schema.Resource{
 		Create: metricCreate,
@@ -38,7 +50,7 @@ func resourceMetric() *schema.Resource {
 		Delete: metricDelete,
 		Exists: metricExists,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: metricImport,
 		},
 
 		Schema: convertToHelperSchema(metricDescriptions, map[schemaAttr]*schema.Schema{
@@ -52,11 +64,17 @@ func resourceMetric() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
 			},
+			metricTagsAttr: tagMakeConfigSchema(metricTagsAttr),
 			metricTypeAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validateStringIn(metricTypeAttr, validMetricTypes),
 			},
+			metricUnitsAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(metricUnitsAttr, metricUnitsRegexp),
+			},
 		}),
 	}
 }
@@ -125,3 +143,26 @@ func metricExists(d *schema.ResourceData, meta interface{}) (bool, error) {
 
 	return false, nil
 }
+
+// metricImport imports a metric either by a new, random ID (the metric is
+// instantiated by a referencing check) or by the CID of an existing metric, in
+// which case the attributes of the metric are read from the API.
+func metricImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cid := d.Id()
+	if !regexp.MustCompile(config.MetricCIDRegex).MatchString(cid) {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	ctxt := meta.(*providerContext)
+	m, err := ctxt.client.FetchMetric(api.CIDType(&cid))
+	if err != nil {
+		return nil, fmt.Errorf("unable to import metric %q: %w", cid, err)
+	}
+
+	d.SetId(m.CID)
+	_ = d.Set(metricActiveAttr, m.Active)
+	_ = d.Set(metricNameAttr, m.MetricName)
+	_ = d.Set(metricTypeAttr, m.MetricType)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,392 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_metric_cluster.* resource attribute names
+	metricClusterDescriptionAttr = "description"
+	metricClusterNameAttr        = "name"
+	metricClusterQueryAttr       = "query"
+	metricClusterTagsAttr        = "tags"
+
+	// circonus_metric_cluster.query.* resource attribute names
+	metricClusterDefinitionAttr  = "definition"
+	metricClusterPatternAttr     = "pattern"
+	metricClusterPatternTypeAttr = "pattern_type"
+	metricClusterTypeAttr        = "type"
+)
+
+const (
+	// circonus_metric_cluster.query.pattern_type values
+	metricClusterPatternTypeGlob  = "glob"
+	metricClusterPatternTypeRegex = "regex"
+)
+
+var metricClusterDescriptions = attrDescrs{
+	metricClusterDescriptionAttr: "A description of the metric cluster",
+	metricClusterNameAttr:        "The name of the metric cluster",
+	metricClusterQueryAttr:       "An ordered list of queries that select the metrics in the metric cluster",
+	metricClusterTagsAttr:        "A list of tags assigned to the metric cluster",
+}
+
+var metricClusterQueryDescriptions = attrDescrs{
+	metricClusterDefinitionAttr:  "A tag and/or metric name query used to match metrics (e.g. `*` `and(env:prod)`)",
+	metricClusterPatternAttr:     "A metric name pattern used to match metrics, an alternative to definition",
+	metricClusterPatternTypeAttr: "The kind of pattern, `glob` (e.g. `cpu*`) or `regex` (e.g. `^cpu[0-9]+`)",
+	metricClusterTypeAttr:        "The type of data the query selects (e.g. `average` or `count`)",
+}
+
+func resourceMetricCluster() *schema.Resource {
+	return &schema.Resource{
+		Create: metricClusterCreate,
+		Read:   metricClusterRead,
+		Update: metricClusterUpdate,
+		Delete: metricClusterDelete,
+		Exists: metricClusterExists,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: metricClusterCustomizeDiff,
+
+		Schema: convertToHelperSchema(metricClusterDescriptions, map[schemaAttr]*schema.Schema{
+			metricClusterDescriptionAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: suppressWhitespace,
+			},
+			metricClusterNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(metricClusterNameAttr, `.+`),
+			},
+			metricClusterQueryAttr: {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(metricClusterQueryDescriptions, map[schemaAttr]*schema.Schema{
+						metricClusterDefinitionAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(metricClusterDefinitionAttr, `\S`),
+							StateFunc:    suppressWhitespace,
+						},
+						metricClusterPatternAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(metricClusterPatternAttr, `\S`),
+						},
+						metricClusterPatternTypeAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      metricClusterPatternTypeGlob,
+							ValidateFunc: validateStringIn(metricClusterPatternTypeAttr, validMetricClusterPatternTypes),
+						},
+						metricClusterTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateStringIn(metricClusterTypeAttr, validMetricClusterTypes),
+						},
+					}),
+				},
+			},
+			metricClusterTagsAttr: tagMakeConfigSchema(metricClusterTagsAttr),
+		}),
+	}
+}
+
+func metricClusterCreate(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+	mc := newMetricCluster()
+	if err := mc.ParseConfig(d); err != nil {
+		return fmt.Errorf("error parsing metric cluster schema during create: %w", err)
+	}
+
+	mc.Tags = ctxt.tagsWithDefaults(mc.Tags)
+
+	if err := mc.Create(ctxt); err != nil {
+		return fmt.Errorf("error creating metric cluster: %w", err)
+	}
+
+	d.SetId(mc.CID)
+
+	return metricClusterRead(d, meta)
+}
+
+func metricClusterExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	mc, err := ctxt.client.FetchMetricCluster(api.CIDType(&cid), "")
+	if err != nil {
+		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if mc.CID == "" {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// metricClusterRead pulls data out of the MetricCluster object and stores it
+// into the appropriate place in the statefile.
+func metricClusterRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	mc, err := loadMetricCluster(ctxt, api.CIDType(&cid))
+	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.SetId(mc.CID)
+
+	queries := make([]interface{}, 0, len(mc.Queries))
+	for i, query := range mc.Queries {
+		queryAttrs := map[string]interface{}{
+			string(metricClusterDefinitionAttr):  query.Query,
+			string(metricClusterPatternAttr):     "",
+			string(metricClusterPatternTypeAttr): metricClusterPatternTypeGlob,
+			string(metricClusterTypeAttr):        query.Type,
+		}
+
+		// queries configured as a pattern are stored as a pattern again, the
+		// API only knows the resulting query
+		prefix := fmt.Sprintf("%s.%d.", metricClusterQueryAttr, i)
+		if d.Get(prefix+metricClusterPatternAttr).(string) != "" {
+			pattern, patternType := metricClusterQueryToPattern(query.Query, d.Get(prefix+metricClusterPatternTypeAttr).(string))
+			queryAttrs[string(metricClusterDefinitionAttr)] = ""
+			queryAttrs[string(metricClusterPatternAttr)] = pattern
+			queryAttrs[string(metricClusterPatternTypeAttr)] = patternType
+		}
+
+		queries = append(queries, queryAttrs)
+	}
+
+	_ = d.Set(metricClusterDescriptionAttr, mc.Description)
+	_ = d.Set(metricClusterNameAttr, mc.Name)
+
+	if err := d.Set(metricClusterQueryAttr, queries); err != nil {
+		return fmt.Errorf("Unable to store metric cluster %q attribute: %w", metricClusterQueryAttr, err)
+	}
+
+	tags := ctxt.tagsWithoutDefaults(mc.Tags, derefStringList(flattenSet(d.Get(metricClusterTagsAttr).(*schema.Set))))
+	if err := d.Set(metricClusterTagsAttr, tagsToState(apiToTags(tags))); err != nil {
+		return fmt.Errorf("Unable to store metric cluster %q attribute: %w", metricClusterTagsAttr, err)
+	}
+
+	return nil
+}
+
+func metricClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+	mc := newMetricCluster()
+	if err := mc.ParseConfig(d); err != nil {
+		return err
+	}
+
+	mc.Tags = ctxt.tagsWithDefaults(mc.Tags)
+
+	mc.CID = d.Id()
+	if err := mc.Update(ctxt); err != nil {
+		return fmt.Errorf("unable to update metric cluster %q: %w", d.Id(), err)
+	}
+
+	return metricClusterRead(d, meta)
+}
+
+func metricClusterDelete(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	if _, err := ctxt.client.DeleteMetricClusterByCID(api.CIDType(&cid)); err != nil {
+		return fmt.Errorf("unable to delete metric cluster %q: %w", d.Id(), err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+type circonusMetricCluster struct {
+	api.MetricCluster
+}
+
+func newMetricCluster() circonusMetricCluster {
+	return circonusMetricCluster{
+		MetricCluster: *api.NewMetricCluster(),
+	}
+}
+
+func loadMetricCluster(ctxt *providerContext, cid api.CIDType) (circonusMetricCluster, error) {
+	var mc circonusMetricCluster
+	cmc, err := ctxt.client.FetchMetricCluster(cid, "")
+	if err != nil {
+		return circonusMetricCluster{}, err
+	}
+	mc.MetricCluster = *cmc
+
+	return mc, nil
+}
+
+// ParseConfig reads Terraform config data and stores the information into a
+// Circonus MetricCluster object.  ParseConfig and metricClusterRead() must be
+// kept in sync.
+func (mc *circonusMetricCluster) ParseConfig(d *schema.ResourceData) error {
+	if v, found := d.GetOk(metricClusterDescriptionAttr); found {
+		mc.Description = v.(string)
+	}
+
+	if v, found := d.GetOk(metricClusterNameAttr); found {
+		mc.Name = v.(string)
+	}
+
+	// Queries are stored as a list so that the order of the queries is
+	// preserved when sent to the API.
+	if listRaw, found := d.GetOk(metricClusterQueryAttr); found {
+		queryList := listRaw.([]interface{})
+		mc.Queries = make([]api.MetricQuery, 0, len(queryList))
+		for _, queryListElem := range queryList {
+			queryAttrs := newInterfaceMap(queryListElem.(map[string]interface{}))
+
+			var query api.MetricQuery
+
+			if v, found := queryAttrs[metricClusterDefinitionAttr]; found {
+				query.Query = strings.TrimSpace(v.(string))
+			}
+
+			if v, found := queryAttrs[metricClusterPatternAttr]; found && v.(string) != "" {
+				if query.Query != "" {
+					return fmt.Errorf("Error with %s[%d]: only one of %s and %s can be set", metricClusterQueryAttr, len(mc.Queries), metricClusterDefinitionAttr, metricClusterPatternAttr)
+				}
+
+				q, err := metricClusterPatternQuery(v.(string), queryAttrs[metricClusterPatternTypeAttr].(string))
+				if err != nil {
+					return fmt.Errorf("Error with %s[%d]: %w", metricClusterQueryAttr, len(mc.Queries), err)
+				}
+				query.Query = q
+			}
+
+			if v, found := queryAttrs[metricClusterTypeAttr]; found {
+				query.Type = v.(string)
+			}
+
+			mc.Queries = append(mc.Queries, query)
+		}
+	}
+
+	if v, found := d.GetOk(metricClusterTagsAttr); found {
+		mc.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	}
+
+	if err := mc.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (mc *circonusMetricCluster) Create(ctxt *providerContext) error {
+	cmc, err := ctxt.client.CreateMetricCluster(&mc.MetricCluster)
+	if err != nil {
+		return err
+	}
+
+	mc.CID = cmc.CID
+
+	return nil
+}
+
+func (mc *circonusMetricCluster) Update(ctxt *providerContext) error {
+	_, err := ctxt.client.UpdateMetricCluster(&mc.MetricCluster)
+	if err != nil {
+		return fmt.Errorf("Unable to update metric cluster %s: %w", mc.CID, err)
+	}
+
+	return nil
+}
+
+func (mc *circonusMetricCluster) Validate() error {
+	if len(mc.Queries) == 0 {
+		return fmt.Errorf("metric cluster %q must have at least one %s", mc.Name, metricClusterQueryAttr)
+	}
+
+	for i, query := range mc.Queries {
+		if query.Query == "" {
+			return fmt.Errorf("Error with %s[%d]: one of %s or %s must be set", metricClusterQueryAttr, i, metricClusterDefinitionAttr, metricClusterPatternAttr)
+		}
+	}
+
+	return nil
+}
+
+// metricClusterCustomizeDiff verifies that the regex patterns of the queries
+// compile at plan time.  Patterns that are not yet known are skipped.
+func metricClusterCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(metricClusterQueryAttr) {
+		return nil
+	}
+
+	for i, queryListElem := range d.Get(metricClusterQueryAttr).([]interface{}) {
+		queryAttrs, ok := queryListElem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		pattern, _ := queryAttrs[string(metricClusterPatternAttr)].(string)
+		patternType, _ := queryAttrs[string(metricClusterPatternTypeAttr)].(string)
+		if pattern == "" {
+			continue
+		}
+
+		if _, err := metricClusterPatternQuery(pattern, patternType); err != nil {
+			return fmt.Errorf("Error with %s[%d]: %w", metricClusterQueryAttr, i, err)
+		}
+	}
+
+	return nil
+}
+
+// metricClusterPatternQuery returns the metric cluster query matching metric
+// names against pattern.  Glob patterns are sent as is, regex patterns are
+// sent delimited by slashes (e.g. /^cpu[0-9]+/), the search syntax for regular
+// expressions, and must compile.
+func metricClusterPatternQuery(pattern, patternType string) (string, error) {
+	switch patternType {
+	case metricClusterPatternTypeRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return "", fmt.Errorf("invalid %s %q: %w", metricClusterPatternAttr, pattern, err)
+		}
+		return "/" + pattern + "/", nil
+	default:
+		return pattern, nil
+	}
+}
+
+// metricClusterQueryToPattern is the inverse of metricClusterPatternQuery, a
+// slash delimited query is a regex pattern.
+func metricClusterQueryToPattern(query, patternType string) (string, string) {
+	if patternType == metricClusterPatternTypeRegex && len(query) > 1 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/") {
+		return query[1 : len(query)-1], metricClusterPatternTypeRegex
+	}
+
+	return query, metricClusterPatternTypeGlob
+}
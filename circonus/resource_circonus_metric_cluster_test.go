@@ -0,0 +1,166 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCirconusMetricCluster_basic(t *testing.T) {
+	metricClusterName := fmt.Sprintf("job1-stream-agg - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusMetricCluster,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusMetricClusterConfigFmt, metricClusterName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "description", "Metric Cluster Description"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "name", metricClusterName),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.#", "2"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.0.definition", "*`nomad-jobname`memory`rss"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.0.type", "average"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.1.definition", "*`nomad-jobname`memory`rss and(env:prod)"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.1.type", "count"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "tags.0", "author:terraform"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "tags.1", "source:nomad"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusMetricClusterPatternConfigFmt, metricClusterName, "^nomad-job[0-9]+`memory`rss$"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.#", "2"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.0.pattern", "*`nomad-jobname`memory`rss"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.0.pattern_type", "glob"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.1.pattern", "^nomad-job[0-9]+`memory`rss$"),
+					resource.TestCheckResourceAttr("circonus_metric_cluster.nomad-job1", "query.1.pattern_type", "regex"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusMetricClusterPatternConfigFmt, metricClusterName, "nomad-job[0-9"),
+				ExpectError: regexp.MustCompile(`invalid pattern`),
+			},
+		},
+	})
+}
+
+func TestMetricClusterPatternQuery(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		patternType string
+		query       string
+		err         bool
+	}{
+		{"cpu*", metricClusterPatternTypeGlob, "cpu*", false},
+		{"^cpu[0-9]+$", metricClusterPatternTypeRegex, "/^cpu[0-9]+$/", false},
+		{"cpu[0-9", metricClusterPatternTypeRegex, "", true},
+	}
+
+	for _, test := range tests {
+		query, err := metricClusterPatternQuery(test.pattern, test.patternType)
+		if (err != nil) != test.err {
+			t.Errorf("%q (%s): unexpected error result: %v", test.pattern, test.patternType, err)
+			continue
+		}
+		if query != test.query {
+			t.Errorf("%q (%s): expected query %q, got %q", test.pattern, test.patternType, test.query, query)
+		}
+		if err != nil {
+			continue
+		}
+
+		pattern, patternType := metricClusterQueryToPattern(query, test.patternType)
+		if pattern != test.pattern || patternType != test.patternType {
+			t.Errorf("%q: expected pattern %q (%s), got %q (%s)", query, test.pattern, test.patternType, pattern, patternType)
+		}
+	}
+}
+
+func testAccCheckDestroyCirconusMetricCluster(s *terraform.State) error {
+	ctxt := testAccProvider.Meta().(*providerContext)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "circonus_metric_cluster" {
+			continue
+		}
+
+		cid := rs.Primary.ID
+		exists, err := checkMetricClusterExists(ctxt, api.CIDType(&cid))
+		switch {
+		case !exists:
+			// noop
+		case exists:
+			return fmt.Errorf("metric cluster still exists after destroy")
+		case err != nil:
+			return fmt.Errorf("Error checking metric cluster: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func checkMetricClusterExists(c *providerContext, metricClusterCID api.CIDType) (bool, error) {
+	mc, err := c.client.FetchMetricCluster(metricClusterCID, "")
+	if err != nil {
+		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if api.CIDType(&mc.CID) == metricClusterCID {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+const testAccCirconusMetricClusterConfigFmt = `
+resource "circonus_metric_cluster" "nomad-job1" {
+  description = <<EOF
+Metric Cluster Description
+EOF
+  name = "%s"
+
+  query {
+    definition = "*` + "`" + `nomad-jobname` + "`" + `memory` + "`" + `rss"
+    type = "average"
+  }
+
+  query {
+    definition = "*` + "`" + `nomad-jobname` + "`" + `memory` + "`" + `rss and(env:prod)"
+    type = "count"
+  }
+
+  tags = [ "author:terraform", "source:nomad" ]
+}
+`
+
+const testAccCirconusMetricClusterPatternConfigFmt = `
+resource "circonus_metric_cluster" "nomad-job1" {
+  name = "%s"
+
+  query {
+    pattern = "*` + "`" + `nomad-jobname` + "`" + `memory` + "`" + `rss"
+    type = "average"
+  }
+
+  query {
+    pattern = "%s"
+    pattern_type = "regex"
+    type = "count"
+  }
+
+  tags = [ "author:terraform", "source:nomad" ]
+}
+`
@@ -59,6 +59,7 @@ func TestAccCirconusMetric_tagsets(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("circonus_metric.t", "name", metricName),
 					resource.TestCheckResourceAttr("circonus_metric.t", "type", "numeric"),
+					resource.TestCheckResourceAttr("circonus_metric.t", "tags.#", "2"),
 				),
 			},
 			{
@@ -66,6 +67,7 @@ func TestAccCirconusMetric_tagsets(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("circonus_metric.t", "name", metricName),
 					resource.TestCheckResourceAttr("circonus_metric.t", "type", "numeric"),
+					resource.TestCheckResourceAttr("circonus_metric.t", "tags.#", "1"),
 				),
 			},
 			{
@@ -73,6 +75,8 @@ func TestAccCirconusMetric_tagsets(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("circonus_metric.t", "name", metricName),
 					resource.TestCheckResourceAttr("circonus_metric.t", "type", "numeric"),
+					resource.TestCheckResourceAttr("circonus_metric.t", "tags.#", "1"),
+					resource.TestCheckResourceAttr("circonus_metric.t", "units", "bytes"),
 				),
 			},
 			{
@@ -80,6 +84,7 @@ func TestAccCirconusMetric_tagsets(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("circonus_metric.t", "name", metricName),
 					resource.TestCheckResourceAttr("circonus_metric.t", "type", "numeric"),
+					resource.TestCheckResourceAttr("circonus_metric.t", "tags.#", "0"),
 				),
 			},
 			{
@@ -142,6 +147,7 @@ const testAccCirconusMetricTagsFmt1 = `
 resource "circonus_metric" "t" {
   name = "%s"
   type = "numeric"
+  tags = [ "author:terraform", "source:circonus" ]
 }
 `
 
@@ -149,6 +155,7 @@ const testAccCirconusMetricTagsFmt2 = `
 resource "circonus_metric" "t" {
   name = "%s"
   type = "numeric"
+  tags = [ "author:terraform" ]
 }
 `
 
@@ -156,6 +163,8 @@ const testAccCirconusMetricTagsFmt3 = `
 resource "circonus_metric" "t" {
   name = "%s"
   type = "numeric"
+  tags = [ "author:terraform" ]
+  units = "bytes"
 }
 `
 
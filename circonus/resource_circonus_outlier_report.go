@@ -0,0 +1,218 @@
+package circonus
+
+import (
+	"fmt"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_outlier_report.* resource attribute names
+	outlierReportConfigAttr        = "config"
+	outlierReportMetricClusterAttr = "metric_cluster"
+	outlierReportTagsAttr          = "tags"
+	outlierReportTitleAttr         = "title"
+)
+
+var outlierReportDescriptions = attrDescrs{
+	outlierReportConfigAttr:        "The configuration of the outlier detection, as understood by the API",
+	outlierReportMetricClusterAttr: "The CID of the metric cluster whose members are compared",
+	outlierReportTagsAttr:          "A list of tags assigned to the outlier report",
+	outlierReportTitleAttr:         "The title of the outlier report",
+}
+
+func resourceOutlierReport() *schema.Resource {
+	return &schema.Resource{
+		Create: outlierReportCreate,
+		Read:   outlierReportRead,
+		Update: outlierReportUpdate,
+		Delete: outlierReportDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthroughUnescape,
+		},
+
+		Schema: convertToHelperSchema(outlierReportDescriptions, map[schemaAttr]*schema.Schema{
+			outlierReportConfigAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			outlierReportMetricClusterAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(outlierReportMetricClusterAttr, config.MetricClusterCIDRegex),
+			},
+			outlierReportTagsAttr: tagMakeConfigSchema(outlierReportTagsAttr),
+			outlierReportTitleAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(outlierReportTitleAttr, `.+`),
+			},
+		}),
+	}
+}
+
+func outlierReportCreate(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+	or := newOutlierReport()
+	if err := or.ParseConfig(d); err != nil {
+		return fmt.Errorf("error parsing outlier report schema during create: %w", err)
+	}
+
+	or.Tags = ctxt.tagsWithDefaults(or.Tags)
+
+	if err := or.Create(ctxt); err != nil {
+		return fmt.Errorf("error creating outlier report: %w", err)
+	}
+
+	d.SetId(or.CID)
+
+	return outlierReportRead(d, meta)
+}
+
+// outlierReportRead pulls data out of the OutlierReport object and stores it
+// into the appropriate place in the statefile.
+func outlierReportRead(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	or, err := loadOutlierReport(ctxt, api.CIDType(&cid))
+	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.SetId(or.CID)
+
+	_ = d.Set(outlierReportConfigAttr, or.Config)
+	_ = d.Set(outlierReportMetricClusterAttr, or.MetricClusterCID)
+	_ = d.Set(outlierReportTitleAttr, or.Title)
+
+	tags := ctxt.tagsWithoutDefaults(or.Tags, derefStringList(flattenSet(d.Get(outlierReportTagsAttr).(*schema.Set))))
+	if err := d.Set(outlierReportTagsAttr, tagsToState(apiToTags(tags))); err != nil {
+		return fmt.Errorf("Unable to store outlier report %q attribute: %w", outlierReportTagsAttr, err)
+	}
+
+	return nil
+}
+
+func outlierReportUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+	or := newOutlierReport()
+	if err := or.ParseConfig(d); err != nil {
+		return err
+	}
+
+	or.Tags = ctxt.tagsWithDefaults(or.Tags)
+
+	or.CID = d.Id()
+	if err := or.Update(ctxt); err != nil {
+		return fmt.Errorf("unable to update outlier report %q: %w", d.Id(), err)
+	}
+
+	return outlierReportRead(d, meta)
+}
+
+func outlierReportDelete(d *schema.ResourceData, meta interface{}) error {
+	ctxt := meta.(*providerContext)
+
+	cid := d.Id()
+	if _, err := ctxt.client.DeleteOutlierReportByCID(api.CIDType(&cid)); err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("unable to delete outlier report %q: %w", d.Id(), err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+type circonusOutlierReport struct {
+	api.OutlierReport
+}
+
+func newOutlierReport() circonusOutlierReport {
+	return circonusOutlierReport{
+		OutlierReport: *api.NewOutlierReport(),
+	}
+}
+
+func loadOutlierReport(ctxt *providerContext, cid api.CIDType) (circonusOutlierReport, error) {
+	var or circonusOutlierReport
+	cor, err := ctxt.client.FetchOutlierReport(cid)
+	if err != nil {
+		return circonusOutlierReport{}, err
+	}
+	or.OutlierReport = *cor
+
+	return or, nil
+}
+
+// ParseConfig reads Terraform config data and stores the information into a
+// Circonus OutlierReport object.  ParseConfig and outlierReportRead() must be
+// kept in sync.
+func (or *circonusOutlierReport) ParseConfig(d *schema.ResourceData) error {
+	if v, found := d.GetOk(outlierReportConfigAttr); found {
+		or.Config = v.(string)
+	}
+
+	if v, found := d.GetOk(outlierReportMetricClusterAttr); found {
+		or.MetricClusterCID = v.(string)
+	}
+
+	if v, found := d.GetOk(outlierReportTagsAttr); found {
+		or.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	}
+
+	if v, found := d.GetOk(outlierReportTitleAttr); found {
+		or.Title = v.(string)
+	}
+
+	return nil
+}
+
+func (or *circonusOutlierReport) Create(ctxt *providerContext) error {
+	if err := or.Validate(ctxt); err != nil {
+		return err
+	}
+
+	cor, err := ctxt.client.CreateOutlierReport(&or.OutlierReport)
+	if err != nil {
+		return err
+	}
+
+	or.CID = cor.CID
+
+	return nil
+}
+
+func (or *circonusOutlierReport) Update(ctxt *providerContext) error {
+	if err := or.Validate(ctxt); err != nil {
+		return err
+	}
+
+	_, err := ctxt.client.UpdateOutlierReport(&or.OutlierReport)
+	if err != nil {
+		return fmt.Errorf("Unable to update outlier report %s: %w", or.CID, err)
+	}
+
+	return nil
+}
+
+// Validate verifies that the referenced metric cluster exists, the API
+// accepts reports of unknown clusters which then never report anything.
+func (or *circonusOutlierReport) Validate(ctxt *providerContext) error {
+	cid := or.MetricClusterCID
+	if _, err := ctxt.client.FetchMetricCluster(api.CIDType(&cid), ""); err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("%s %q does not exist", outlierReportMetricClusterAttr, cid)
+		}
+		return fmt.Errorf("unable to fetch %s %q: %w", outlierReportMetricClusterAttr, cid, err)
+	}
+
+	return nil
+}
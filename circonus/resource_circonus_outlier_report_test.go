@@ -0,0 +1,88 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCirconusOutlierReport_basic(t *testing.T) {
+	reportTitle := fmt.Sprintf("Terraform test: outlier report - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusOutlierReport,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusOutlierReportConfigFmt, reportTitle),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_outlier_report.rss", "title", reportTitle),
+					resource.TestCheckResourceAttrPair("circonus_outlier_report.rss", "metric_cluster", "circonus_metric_cluster.rss", "id"),
+					resource.TestCheckResourceAttr("circonus_outlier_report.rss", "tags.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "circonus_outlier_report.rss",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusOutlierReportMissingClusterConfigFmt, reportTitle),
+				ExpectError: regexp.MustCompile(`does not exist`),
+			},
+		},
+	})
+}
+
+func testAccCheckDestroyCirconusOutlierReport(s *terraform.State) error {
+	ctxt := testAccProvider.Meta().(*providerContext)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "circonus_outlier_report" {
+			continue
+		}
+
+		cid := rs.Primary.ID
+		_, err := ctxt.client.FetchOutlierReport(api.CIDType(&cid))
+		switch {
+		case api.IsNotFound(err):
+			// noop
+		case err != nil:
+			return fmt.Errorf("Error checking outlier report: %v", err)
+		default:
+			return fmt.Errorf("outlier report still exists after destroy")
+		}
+	}
+
+	return nil
+}
+
+const testAccCirconusOutlierReportConfigFmt = `
+resource "circonus_metric_cluster" "rss" {
+  name = "rss"
+
+  query {
+    definition = "*` + "`" + `memory` + "`" + `rss"
+    type = "average"
+  }
+}
+
+resource "circonus_outlier_report" "rss" {
+  title = "%s"
+  metric_cluster = circonus_metric_cluster.rss.id
+  tags = [ "author:terraform" ]
+}
+`
+
+const testAccCirconusOutlierReportMissingClusterConfigFmt = `
+resource "circonus_outlier_report" "rss" {
+  title = "%s"
+  metric_cluster = "/metric_cluster/0"
+}
+`
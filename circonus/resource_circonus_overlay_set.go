@@ -1,11 +1,16 @@
 package circonus
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -27,25 +32,33 @@ func resourceOverlaySet() *schema.Resource {
 		Delete: overlaySetDelete,
 		Exists: overlaySetExists,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: overlaySetImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"graph_cid": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRegexp("graph_cid", config.GraphCIDRegex),
 			},
 			"title": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"overlays": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Required: true,
+				MinItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp("id", `\S`),
+						},
+						"title": {
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
 						},
 						"data_opts": {
 							Type:     schema.TypeSet,
@@ -54,15 +67,39 @@ func resourceOverlaySet() *schema.Resource {
 								Schema: map[string]*schema.Schema{
 									"graph_title": {
 										Type:     schema.TypeString,
-										Required: true,
+										Optional: true,
 									},
 									"graph_uuid": {
 										Type:     schema.TypeString,
-										Required: true,
+										Optional: true,
+									},
+									"model": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"model_end": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"model_period": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"season_length": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"sensitivity": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"target_period": {
+										Type:     schema.TypeString,
+										Optional: true,
 									},
 									"x_shift": {
 										Type:     schema.TypeString,
-										Required: true,
+										Optional: true,
 									},
 								},
 							},
@@ -115,6 +152,8 @@ func overlaySetCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error creating graph: %w", err)
 	}
 
+	d.SetId(o.OverlaySetID)
+
 	return overlaySetRead(d, meta)
 }
 
@@ -160,14 +199,20 @@ func overlaySetRead(d *schema.ResourceData, meta interface{}) error {
 		s := graph_cid.(string)
 		g, err := loadOverlaySet(ctxt, api.CIDType(&s), id)
 		if err != nil {
+			if api.IsNotFound(err) {
+				// the resource was deleted outside of terraform
+				d.SetId("")
+				return nil
+			}
 			return err
 		}
 
 		_ = d.Set("graph_cid", graph_cid)
 		_ = d.Set("title", g.GraphOverlaySet.Title)
 
-		dOverlays := make([]map[string]interface{}, len(g.GraphOverlaySet.Overlays))
-		for _, overlay := range g.GraphOverlaySet.Overlays {
+		dOverlays := make([]map[string]interface{}, 0, len(g.GraphOverlaySet.Overlays))
+		for _, overlayID := range overlaySetOrder(d, g.GraphOverlaySet.Overlays) {
+			overlay := g.GraphOverlaySet.Overlays[overlayID]
 			this_overlay := make(map[string]interface{}, 4)
 
 			uiSpecs := make(map[string]interface{}, 5)
@@ -184,14 +229,14 @@ func overlaySetRead(d *schema.ResourceData, meta interface{}) error {
 			set[0] = uiSpecs
 			this_overlay["ui_specs"] = set
 
-			dataOpts := make(map[string]interface{}, 3)
-			dataOpts["graph_title"] = overlay.DataOpts.GraphTitle
-			dataOpts["graph_uuid"] = overlay.DataOpts.GraphUUID
-			dataOpts["x_shift"] = overlay.DataOpts.XShift
+			dataOpts := make(map[string]interface{}, 9)
+			for k, v := range overlayDataOptsValues(overlay.DataOpts) {
+				dataOpts[k] = v
+			}
 
 			set = make([]map[string]interface{}, 1)
 			set[0] = dataOpts
-			this_overlay["data_opt"] = set
+			this_overlay["data_opts"] = set
 
 			dOverlays = append(dOverlays, this_overlay)
 		}
@@ -201,6 +246,71 @@ func overlaySetRead(d *schema.ResourceData, meta interface{}) error {
 	return fmt.Errorf("graph_cid field is required for %q", d.Id())
 }
 
+// overlaySetOrder returns the IDs of the overlays in the order they appear in
+// the current state, followed by any overlays not in the state (e.g. on
+// import) sorted by ID.  The API stores overlays in a map and does not
+// preserve their order.
+func overlaySetOrder(d *schema.ResourceData, overlays map[string]api.GraphOverlay) []string {
+	ids := make([]string, 0, len(overlays))
+	seen := make(map[string]bool, len(overlays))
+
+	if v, found := d.GetOk("overlays"); found {
+		for _, overlaySpec := range v.([]interface{}) {
+			overlayMap, ok := overlaySpec.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := overlayMap["id"].(string)
+			if _, ok := overlays[id]; ok && !seen[id] {
+				ids = append(ids, id)
+				seen[id] = true
+			}
+		}
+	}
+
+	remaining := make([]string, 0, len(overlays))
+	for id := range overlays {
+		if !seen[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(ids, remaining...)
+}
+
+var overlaySetImportIDRegexp = regexp.MustCompile(`^(` + config.GraphPrefix + `/.+)/([^/]+)$`)
+
+// overlaySetImport imports an overlay set using an ID of the form
+// <graph_cid>/<overlay_set_id> (e.g. /graph/1234/abcdef).
+func overlaySetImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	m := overlaySetImportIDRegexp.FindStringSubmatch(d.Id())
+	if m == nil {
+		return nil, fmt.Errorf("invalid overlay set import ID %q, expected <graph_cid>/<overlay_set_id>", d.Id())
+	}
+
+	ctxt := meta.(*providerContext)
+	graphCID := m[1]
+	id := m[2]
+
+	g, err := ctxt.client.FetchGraph(api.CIDType(&graphCID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to import overlay set %q: %w", d.Id(), err)
+	}
+
+	if g.OverlaySets == nil {
+		return nil, fmt.Errorf("overlay set %q not found on graph %q", id, graphCID)
+	}
+	if _, ok := (*g.OverlaySets)[id]; !ok {
+		return nil, fmt.Errorf("overlay set %q not found on graph %q", id, graphCID)
+	}
+
+	d.SetId(id)
+	_ = d.Set("graph_cid", graphCID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func overlaySetUpdate(d *schema.ResourceData, meta interface{}) error {
 	ctxt := meta.(*providerContext)
 	g := newOverlaySet()
@@ -266,12 +376,18 @@ func loadOverlaySet(ctxt *providerContext, graph_cid api.CIDType, set_id string)
 	if err != nil {
 		return circonusOverlaySet{}, err
 	}
-	if ng.OverlaySets == nil {
-		return circonusOverlaySet{}, nil
+	// the overlay set was removed from the graph, e.g. outside of terraform
+	var set api.GraphOverlaySet
+	var ok bool
+	if ng.OverlaySets != nil {
+		set, ok = (*ng.OverlaySets)[set_id]
+	}
+	if !ok {
+		return circonusOverlaySet{}, fmt.Errorf("overlay set %q not found on graph %q: %w", set_id, *graph_cid, api.ErrNotFound)
 	}
 
 	g.OverlaySetID = set_id
-	g.GraphOverlaySet = (*ng.OverlaySets)[set_id]
+	g.GraphOverlaySet = set
 	g.GraphCID = *graph_cid
 	return g, nil
 }
@@ -284,7 +400,7 @@ func (g *circonusOverlaySet) ParseConfig(d *schema.ResourceData) error {
 		g.GraphOverlaySet.Title = v.(string)
 	}
 	if v, found := d.GetOk("overlays"); found {
-		overlayList := v.(*schema.Set).List()
+		overlayList := v.([]interface{})
 		for _, overlaySpec := range overlayList {
 			var gOverlay api.GraphOverlay
 			overlayMap := newInterfaceMap(overlaySpec.(map[string]interface{}))
@@ -323,11 +439,32 @@ func (g *circonusOverlaySet) ParseConfig(d *schema.ResourceData) error {
 					if v, found := dataOptMap["graph_uuid"]; found {
 						gOverlay.DataOpts.GraphUUID = v.(string)
 					}
+					if v, found := dataOptMap["model"]; found {
+						gOverlay.DataOpts.Model = v.(string)
+					}
+					if v, found := dataOptMap["model_end"]; found {
+						gOverlay.DataOpts.ModelEnd = v.(string)
+					}
+					if v, found := dataOptMap["model_period"]; found {
+						gOverlay.DataOpts.ModelPeriod = v.(string)
+					}
+					if v, found := dataOptMap["season_length"]; found {
+						gOverlay.DataOpts.SeasonLength = v.(string)
+					}
+					if v, found := dataOptMap["sensitivity"]; found {
+						gOverlay.DataOpts.Sensitivity = v.(string)
+					}
+					if v, found := dataOptMap["target_period"]; found {
+						gOverlay.DataOpts.TargetPeriod = v.(string)
+					}
 					if v, found := dataOptMap["x_shift"]; found {
 						gOverlay.DataOpts.XShift = v.(string)
 					}
 				}
 			}
+			if _, found := g.GraphOverlaySet.Overlays[gOverlay.ID]; found {
+				return fmt.Errorf("overlay id %q may only be used once", gOverlay.ID)
+			}
 			g.GraphOverlaySet.Overlays[gOverlay.ID] = gOverlay
 		}
 	}
@@ -335,6 +472,11 @@ func (g *circonusOverlaySet) ParseConfig(d *schema.ResourceData) error {
 		g.GraphCID = v.(string)
 	}
 	g.OverlaySetID = d.Id()
+
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -369,6 +511,11 @@ func (g *circonusOverlaySet) Update(ctxt *providerContext) error {
 		return err
 	}
 
+	if gg.OverlaySets == nil {
+		set := make(map[string]api.GraphOverlaySet)
+		gg.OverlaySets = &set
+	}
+
 	(*gg.OverlaySets)[g.OverlaySetID] = g.GraphOverlaySet
 
 	_, err = ctxt.client.UpdateGraph(gg)
@@ -380,5 +527,106 @@ func (g *circonusOverlaySet) Update(ctxt *providerContext) error {
 }
 
 func (g *circonusOverlaySet) Validate() error {
+	if g.GraphCID != "" {
+		if ok, _ := regexp.MatchString(config.GraphCIDRegex, g.GraphCID); !ok {
+			return fmt.Errorf("invalid graph_cid %q", g.GraphCID)
+		}
+	}
+
+	for id, overlay := range g.GraphOverlaySet.Overlays {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("overlay id can not be empty")
+		}
+		if err := overlayValidateDataOpts(overlay.UISpecs.Type, overlay.DataOpts); err != nil {
+			return fmt.Errorf("overlay %q: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// overlayDataOpts lists the data_opts that are required and permitted for
+// each known overlay type.  Overlays of other types are passed to the API
+// unchecked.
+var overlayDataOpts = map[string]struct {
+	required []string
+	optional []string
+}{
+	"anomaly_detection": {
+		required: []string{"sensitivity"},
+		optional: []string{"model", "model_period"},
+	},
+	"forecast": {
+		required: []string{"model", "target_period"},
+		optional: []string{"model_period", "season_length"},
+	},
+	"graph_comparison": {
+		required: []string{"graph_title", "graph_uuid", "x_shift"},
+	},
+	"trend": {
+		required: []string{"model"},
+		optional: []string{"model_end", "model_period"},
+	},
+}
+
+// overlayDataOptsValues returns the data_opts managed by circonus_overlay_set
+// keyed by their attribute name.
+func overlayDataOptsValues(opts api.OverlayDataOptions) map[string]string {
+	return map[string]string{
+		"graph_title":   opts.GraphTitle,
+		"graph_uuid":    opts.GraphUUID,
+		"model":         opts.Model,
+		"model_end":     opts.ModelEnd,
+		"model_period":  opts.ModelPeriod,
+		"season_length": opts.SeasonLength,
+		"sensitivity":   opts.Sensitivity,
+		"target_period": opts.TargetPeriod,
+		"x_shift":       opts.XShift,
+	}
+}
+
+// overlayValidateDataOpts verifies that the data_opts of an overlay match
+// what its type requires, the API silently drops overlays it can not render.
+func overlayValidateDataOpts(overlayType string, opts api.OverlayDataOptions) error {
+	spec, known := overlayDataOpts[overlayType]
+	if !known {
+		return nil
+	}
+
+	values := overlayDataOptsValues(opts)
+	permitted := make(map[string]bool, len(spec.required)+len(spec.optional))
+	for _, attr := range spec.required {
+		if values[attr] == "" {
+			return fmt.Errorf("data_opts %q is required for %s overlays", attr, overlayType)
+		}
+		permitted[attr] = true
+	}
+	for _, attr := range spec.optional {
+		permitted[attr] = true
+	}
+
+	attrs := make([]string, 0, len(values))
+	for attr := range values {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+	for _, attr := range attrs {
+		if values[attr] != "" && !permitted[attr] {
+			return fmt.Errorf("data_opts %q is not supported by %s overlays", attr, overlayType)
+		}
+	}
+
+	if opts.SeasonLength != "" {
+		if n, err := strconv.Atoi(opts.SeasonLength); err != nil || n < 1 {
+			return fmt.Errorf("data_opts \"season_length\" must be a positive integer, got %q", opts.SeasonLength)
+		}
+	}
+
+	if opts.Sensitivity != "" {
+		if n, err := strconv.Atoi(opts.Sensitivity); err != nil || n < 0 || n > 100 {
+			return fmt.Errorf("data_opts \"sensitivity\" must be an integer between 0 and 100, got %q", opts.Sensitivity)
+		}
+	}
+
 	return nil
 }
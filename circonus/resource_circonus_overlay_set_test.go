@@ -0,0 +1,224 @@
+package circonus
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCirconusOverlaySet_basic(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+	graphName := fmt.Sprintf("Test Graph - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusOverlaySet,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusOverlaySetConfigFmt, checkName, graphName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "title", "Trends"),
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "overlays.#", "2"),
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "overlays.0.id", "zshift"),
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "overlays.0.ui_specs.#", "1"),
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "overlays.0.data_opts.#", "1"),
+					resource.TestCheckResourceAttr("circonus_overlay_set.trends", "overlays.1.id", "ashift"),
+				),
+			},
+		},
+	})
+}
+
+func TestOverlayValidateDataOpts(t *testing.T) {
+	tests := []struct {
+		overlayType string
+		opts        api.OverlayDataOptions
+		err         string
+	}{
+		{"graph_comparison", api.OverlayDataOptions{GraphTitle: "Last week", GraphUUID: "/graph/1", XShift: "1w"}, ""},
+		{"graph_comparison", api.OverlayDataOptions{GraphTitle: "Last week", GraphUUID: "/graph/1"}, `"x_shift" is required`},
+		{"forecast", api.OverlayDataOptions{Model: "holt-winters", TargetPeriod: "1d", SeasonLength: "24"}, ""},
+		{"forecast", api.OverlayDataOptions{Model: "holt-winters", TargetPeriod: "1d", SeasonLength: "0"}, `"season_length" must be a positive integer`},
+		{"forecast", api.OverlayDataOptions{Model: "holt-winters"}, `"target_period" is required`},
+		{"trend", api.OverlayDataOptions{Model: "linear", ModelPeriod: "1w"}, ""},
+		{"trend", api.OverlayDataOptions{Model: "linear", Sensitivity: "50"}, `"sensitivity" is not supported`},
+		{"anomaly_detection", api.OverlayDataOptions{Sensitivity: "50"}, ""},
+		{"anomaly_detection", api.OverlayDataOptions{Sensitivity: "101"}, `"sensitivity" must be an integer between 0 and 100`},
+		{"anomaly_detection", api.OverlayDataOptions{Sensitivity: "50", XShift: "1w"}, `"x_shift" is not supported`},
+		{"custom", api.OverlayDataOptions{XShift: "1w"}, ""},
+	}
+
+	for _, test := range tests {
+		err := overlayValidateDataOpts(test.overlayType, test.opts)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s %+v: unexpected error %v", test.overlayType, test.opts, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s %+v: expected error containing %q", test.overlayType, test.opts, test.err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s %+v: expected error containing %q, got %v", test.overlayType, test.opts, test.err, err)
+		}
+	}
+}
+
+func TestOverlaySetReadRemoved(t *testing.T) {
+	tests := []struct {
+		overlaySets string
+		exists      bool
+	}{
+		{`null`, false},
+		{`{"other":{"title":"Other","overlays":{}}}`, false},
+		{`{"abcdef":{"title":"Trends","overlays":{}}}`, true},
+	}
+
+	for _, test := range tests {
+		ctxt, srv := testAPIProviderContext(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"_cid":"/graph/1234","title":"Graph","overlay_sets":` + test.overlaySets + `}`))
+		})
+
+		d := schema.TestResourceDataRaw(t, resourceOverlaySet().Schema, map[string]interface{}{
+			"graph_cid": "/graph/1234",
+			"title":     "Trends",
+		})
+		d.SetId("abcdef")
+
+		err := overlaySetRead(d, ctxt)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.overlaySets, err)
+		}
+
+		if exists := d.Id() != ""; exists != test.exists {
+			t.Errorf("%s: expected the overlay set to exist: %t, got ID %q", test.overlaySets, test.exists, d.Id())
+		}
+	}
+}
+
+func testAccCheckDestroyCirconusOverlaySet(s *terraform.State) error {
+	ctxt := testAccProvider.Meta().(*providerContext)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "circonus_overlay_set" {
+			continue
+		}
+
+		graphCID := rs.Primary.Attributes["graph_cid"]
+		exists, err := checkOverlaySetExists(ctxt, api.CIDType(&graphCID), rs.Primary.ID)
+		switch {
+		case !exists:
+			// noop
+		case exists:
+			return fmt.Errorf("overlay set still exists after destroy")
+		case err != nil:
+			return fmt.Errorf("Error checking overlay set: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func checkOverlaySetExists(c *providerContext, graphCID api.CIDType, id string) (bool, error) {
+	g, err := c.client.FetchGraph(graphCID)
+	if err != nil {
+		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if g.OverlaySets == nil {
+		return false, nil
+	}
+
+	_, ok := (*g.OverlaySets)[id]
+
+	return ok, nil
+}
+
+const testAccCirconusOverlaySetConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 5
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_graph" "overlay-graph" {
+  name = "%s"
+  graph_style = "line"
+
+  metric {
+    check = "${circonus_check.api_latency.checks[0]}"
+    metric_name = "maximum"
+    metric_type = "numeric"
+    name = "Maximum Latency"
+    axis = "left"
+    color = "#657aa6"
+    function = "gauge"
+    active = true
+  }
+}
+
+resource "circonus_overlay_set" "trends" {
+  graph_cid = "${circonus_graph.overlay-graph.id}"
+  title = "Trends"
+
+  overlays {
+    id = "zshift"
+
+    data_opts {
+      graph_title = "Last week"
+      graph_uuid = "${circonus_graph.overlay-graph.id}"
+      x_shift = "1w"
+    }
+
+    ui_specs {
+      id = "zshift"
+      label = "1 week ago"
+      type = "graph_comparison"
+      z = "-1"
+    }
+  }
+
+  overlays {
+    id = "ashift"
+
+    data_opts {
+      graph_title = "Yesterday"
+      graph_uuid = "${circonus_graph.overlay-graph.id}"
+      x_shift = "1d"
+    }
+
+    ui_specs {
+      id = "ashift"
+      label = "1 day ago"
+      type = "graph_comparison"
+      z = "-1"
+    }
+  }
+}
+`
@@ -1,11 +1,13 @@
 package circonus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
@@ -15,29 +17,36 @@ import (
 
 const (
 	// circonus_rule_set.* resource attribute names
-	ruleSetCheckAttr         = "check"
-	ruleSetNameAttr          = "name"
-	ruleSetIfAttr            = "if"
-	ruleSetLinkAttr          = "link"
-	ruleSetMetricTypeAttr    = "metric_type"
-	ruleSetNotesAttr         = "notes"
-	ruleSetUserJsonAttr      = "user_json"
-	ruleSetParentAttr        = "parent"
-	ruleSetMetricNameAttr    = "metric_name"
-	ruleSetMetricPatternAttr = "metric_pattern"
-	ruleSetMetricFilterAttr  = "metric_filter"
-	ruleSetTagsAttr          = "tags"
+	ruleSetAllowUnorderedAttr = "allow_unordered_severities"
+	ruleSetCheckAttr          = "check"
+	ruleSetNameAttr           = "name"
+	ruleSetIfAttr             = "if"
+	ruleSetLinkAttr           = "link"
+	ruleSetMetricTypeAttr     = "metric_type"
+	ruleSetNotesAttr          = "notes"
+	ruleSetNotifyAttr         = "notify"
+	ruleSetUserJsonAttr       = "user_json"
+	ruleSetParentAttr         = "parent"
+	ruleSetMetricNameAttr     = "metric_name"
+	ruleSetMetricPatternAttr  = "metric_pattern"
+	ruleSetMetricFilterAttr   = "metric_filter"
+	ruleSetTagsAttr           = "tags"
 
 	// circonus_rule_set.if.* resource attribute names
-	ruleSetThenAttr  = "then"
-	ruleSetValueAttr = "value"
+	ruleSetDurationAttr = "duration"
+	ruleSetThenAttr     = "then"
+	ruleSetValueAttr    = "value"
+	ruleSetWindowAttr   = "window"
 
 	// circonus_rule_set.if.then.* resource attribute names
 	ruleSetAfterAttr    = "after"
-	ruleSetNotifyAttr   = "notify"
 	ruleSetSeverityAttr = "severity"
 
+	// circonus_rule_set.notify.* resource attribute names
+	ruleSetContactGroupsAttr = "contact_groups"
+
 	// circonus_rule_set.if.value.* resource attribute names
+	ruleSetAbsenceAttr    = "absence"     // apiRuleSetAbsent
 	ruleSetAbsentAttr     = "absent"      // apiRuleSetAbsent
 	ruleSetChangedAttr    = "changed"     // apiRuleSetChanged
 	ruleSetContainsAttr   = "contains"    // apiRuleSetContains
@@ -49,6 +58,10 @@ const (
 	ruleSetNotContainAttr = "not_contain" // apiRuleSetNotContains
 	ruleSetNotMatchAttr   = "not_match"   // apiRuleSetNotMatch
 	ruleSetOverAttr       = "over"
+	ruleSetQuantileAttr   = "quantile"
+
+	// circonus_rule_set.if.value.absence.* resource attribute names
+	ruleSetWaitAttr = "wait"
 
 	// circonus_rule_set.if.value.over.* resource attribute names
 	ruleSetLastAttr    = "last"
@@ -75,29 +88,40 @@ const (
 
 var ruleSetDescriptions = attrDescrs{
 	// circonus_rule_set.* resource attribute names
-	ruleSetCheckAttr:         "The CID of the check that contains the metric for this rule set",
-	ruleSetNameAttr:          "The name of this ruleset, if ommitted will default to the metric_name (or pattern) and filter",
-	ruleSetIfAttr:            "A rule to execute for this rule set",
-	ruleSetLinkAttr:          "URL to show users when this rule set is active (e.g. wiki)",
-	ruleSetMetricTypeAttr:    "The type of data flowing through the specified metric stream",
-	ruleSetNotesAttr:         "Notes describing this rule set",
-	ruleSetUserJsonAttr:      "Opaque data that can be supplied with the result and appears in webhooks when alerts go off",
-	ruleSetParentAttr:        "Parent CID that must be healthy for this rule set to be active",
-	ruleSetMetricNameAttr:    "The name of the metric stream within a check to register the rule set with",
-	ruleSetMetricPatternAttr: "The pattern match (regex) of the metric stream within a check to register the rule set with",
-	ruleSetMetricFilterAttr:  "The tag filter a pattern match ruleset will user",
-	ruleSetTagsAttr:          "Tags associated with this rule set",
-	ruleSetIdAttr:            "out",
+	ruleSetCheckAttr:          "The CID of the check that contains the metric for this rule set",
+	ruleSetAllowUnorderedAttr: "Allow thresholds which are not ordered by severity, e.g. a severity 2 max_value above the severity 1 max_value",
+	ruleSetNameAttr:           "The name of this ruleset, if ommitted will default to the metric_name (or pattern) and filter",
+	ruleSetIfAttr:             "A rule to execute for this rule set",
+	ruleSetLinkAttr:           "URL to show users when this rule set is active (e.g. wiki)",
+	ruleSetMetricTypeAttr:     "The type of data flowing through the specified metric stream",
+	ruleSetNotesAttr:          "Notes describing this rule set",
+	ruleSetNotifyAttr:         "Contact groups to notify for a given severity, overriding the contact groups of the rules",
+	ruleSetUserJsonAttr:       "Opaque data that can be supplied with the result and appears in webhooks when alerts go off",
+	ruleSetParentAttr:         "Parent CID that must be healthy for this rule set to be active",
+	ruleSetMetricNameAttr:     "The name of the metric stream within a check to register the rule set with",
+	ruleSetMetricPatternAttr:  "The pattern match (regex) of the metric stream within a check to register the rule set with",
+	ruleSetMetricFilterAttr:   "The tag filter a pattern match ruleset will user",
+	ruleSetTagsAttr:           "Tags associated with this rule set",
+	ruleSetIdAttr:             "out",
+}
+
+var ruleSetNotifyDescriptions = attrDescrs{
+	// circonus_rule_set.notify.* resource attribute names
+	ruleSetContactGroupsAttr: "List of contact groups to notify at this severity",
+	ruleSetSeverityAttr:      "The severity level the contact groups are notified at",
 }
 
 var ruleSetIfDescriptions = attrDescrs{
 	// circonus_rule_set.if.* resource attribute names
-	ruleSetThenAttr:  "Description of the action(s) to take when this rule set is active",
-	ruleSetValueAttr: "Predicate that the rule set uses to evaluate a stream of metrics",
+	ruleSetDurationAttr: "Minimum duration (seconds) of data within the window required before the rule is evaluated",
+	ruleSetThenAttr:     "Description of the action(s) to take when this rule set is active",
+	ruleSetValueAttr:    "Predicate that the rule set uses to evaluate a stream of metrics",
+	ruleSetWindowAttr:   "Duration (seconds) of the sliding window the value must be sustained over",
 }
 
 var ruleSetIfValueDescriptions = attrDescrs{
 	// circonus_rule_set.if.value.* resource attribute names
+	ruleSetAbsenceAttr:    "Fire the rule set if there has been no data for the given metric stream for the wait duration",
 	ruleSetAbsentAttr:     "Fire the rule set if there has been no data for the given metric stream over the last duration",
 	ruleSetChangedAttr:    "Boolean indicating the value has changed",
 	ruleSetContainsAttr:   "Fire the rule set if the text metric contain the following string",
@@ -109,9 +133,15 @@ var ruleSetIfValueDescriptions = attrDescrs{
 	ruleSetNotContainAttr: "Fire the rule set if the text metric does not contain the following string",
 	ruleSetMaxValueAttr:   "Fire the rule set if the numeric value is more than the specified value",
 	ruleSetOverAttr:       "Use a derived value using a window",
+	ruleSetQuantileAttr:   "Compare min_value or max_value against this quantile (0 to 1) of a histogram metric, e.g. 0.99 for the 99th percentile",
 	ruleSetThenAttr:       "Action to take when the rule set is active",
 }
 
+var ruleSetIfValueAbsenceDescriptions = attrDescrs{
+	// circonus_rule_set.if.value.absence.* resource attribute names
+	ruleSetWaitAttr: "Duration (seconds) without data after which the rule fires",
+}
+
 var ruleSetIfValueOverDescriptions = attrDescrs{
 	// circonus_rule_set.if.value.over.* resource attribute names
 	ruleSetLastAttr:    "Duration over which data from the last interval is examined",
@@ -146,8 +176,14 @@ func resourceRuleSet() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: importStatePassthroughUnescape,
 		},
+		CustomizeDiff: ruleSetCustomizeDiff,
 
 		Schema: convertToHelperSchema(ruleSetDescriptions, map[schemaAttr]*schema.Schema{
+			ruleSetAllowUnorderedAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			ruleSetCheckAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -164,6 +200,11 @@ func resourceRuleSet() *schema.Resource {
 				MinItems: 1,
 				Elem: &schema.Resource{
 					Schema: convertToHelperSchema(ruleSetIfDescriptions, map[schemaAttr]*schema.Schema{
+						ruleSetDurationAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(ruleSetDurationAttr, "^[0-9]+$"),
+						},
 						ruleSetThenAttr: {
 							Type:     schema.TypeList,
 							MaxItems: 1,
@@ -203,6 +244,20 @@ func resourceRuleSet() *schema.Resource {
 							Optional: true,
 							Elem: &schema.Resource{
 								Schema: convertToHelperSchema(ruleSetIfValueDescriptions, map[schemaAttr]*schema.Schema{
+									ruleSetAbsenceAttr: {
+										Type:     schema.TypeList, // Applies to text or numeric metrics
+										Optional: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: convertToHelperSchema(ruleSetIfValueAbsenceDescriptions, map[schemaAttr]*schema.Schema{
+												ruleSetWaitAttr: {
+													Type:         schema.TypeString,
+													Required:     true,
+													ValidateFunc: validateRegexp(ruleSetWaitAttr, "^[1-9][0-9]*$"),
+												},
+											}),
+										},
+									},
 									ruleSetAbsentAttr: {
 										Type:         schema.TypeString, // Applies to text or numeric metrics
 										Optional:     true,
@@ -262,6 +317,11 @@ func resourceRuleSet() *schema.Resource {
 										ValidateFunc: validateRegexp(ruleSetNotEqValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
 										// ConflictsWith: makeConflictsWith(ruleSetAbsentAttr, ruleSetChangedAttr, ruleSetContainsAttr, ruleSetMatchAttr, ruleSetNotMatchAttr, ruleSetMinValueAttr, ruleSetMaxValueAttr, ruleSetEqValueAttr, ruleSetNotContainAttr),
 									},
+									ruleSetQuantileAttr: {
+										Type:         schema.TypeString, // Applies to histogram metrics only
+										Optional:     true,
+										ValidateFunc: validateFloatString(ruleSetQuantileAttr, 0, 1),
+									},
 									ruleSetOverAttr: {
 										Type:     schema.TypeList,
 										Optional: true,
@@ -275,16 +335,19 @@ func resourceRuleSet() *schema.Resource {
 												ruleSetLastAttr: {
 													Type:         schema.TypeString,
 													Optional:     true,
-													ValidateFunc: validateRegexp(ruleSetLastAttr, "^[0-9]+$"),
+													Default:      defaultRuleSetLast,
+													ValidateFunc: validateRegexp(ruleSetLastAttr, "^[1-9][0-9]*$"),
 												},
 												ruleSetAtLeastAttr: {
 													Type:         schema.TypeString,
 													Optional:     true,
+													Default:      defaultRuleSetAtLeast,
 													ValidateFunc: validateRegexp(ruleSetAtLeastAttr, "^[0-9]+$"),
 												},
 												ruleSetUsingAttr: {
 													Type:         schema.TypeString,
 													Optional:     true,
+													Default:      defaultRuleSetWindowFunc,
 													ValidateFunc: validateStringIn(ruleSetUsingAttr, validRuleSetWindowFuncs),
 												},
 											}),
@@ -293,6 +356,11 @@ func resourceRuleSet() *schema.Resource {
 								}),
 							},
 						},
+						ruleSetWindowAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(ruleSetWindowAttr, "^[1-9][0-9]*$"),
+						},
 					}),
 				},
 			},
@@ -350,12 +418,37 @@ func resourceRuleSet() *schema.Resource {
 
 				Default: "{}",
 			},
+			ruleSetNotifyAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: int(config.NumSeverityLevels),
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(ruleSetNotifyDescriptions, map[schemaAttr]*schema.Schema{
+						ruleSetContactGroupsAttr: {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateContactGroupCID(ruleSetContactGroupsAttr),
+							},
+						},
+						ruleSetSeverityAttr: {
+							Type:     schema.TypeInt,
+							Required: true,
+							ValidateFunc: validateFuncs(
+								validateIntMax(ruleSetSeverityAttr, maxSeverity),
+								validateIntMin(ruleSetSeverityAttr, minSeverity),
+							),
+						},
+					}),
+				},
+			},
 			ruleSetParentAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
-				Computed:     true,
 				StateFunc:    suppressWhitespace,
-				ValidateFunc: validateRegexp(ruleSetParentAttr, `^([\d]+(_[\d\w]+)?)|(\/rule_set\/[\d]+)$`),
+				ValidateFunc: validateRegexp(ruleSetParentAttr, `^([\d]+(_.+)?|/rule_set/[\d]+)$`),
 			},
 			ruleSetMetricNameAttr: {
 				Type:         schema.TypeString,
@@ -392,6 +485,20 @@ func ruleSetCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing rule set schema during create: %w", err)
 	}
 
+	rs.Tags = ctxt.tagsWithDefaults(rs.Tags)
+
+	if err := rs.ValidateWindows(ctxt); err != nil {
+		return err
+	}
+
+	if err := rs.ValidateQuantiles(ctxt); err != nil {
+		return err
+	}
+
+	if err := rs.ValidateParent(ctxt); err != nil {
+		return err
+	}
+
 	if err := rs.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating rule set: %w", err)
 	}
@@ -425,6 +532,11 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	rs, err := loadRuleSet(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -432,23 +544,40 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set(ruleSetIdAttr, rs.CID)
 	_ = d.Set(ruleSetNameAttr, rs.Name)
 
+	// Severities managed by a notify block are stored there and not in the
+	// notify attribute of each rule.
+	notifySeverities := ruleSetNotifySeverities(d)
+
 	ifRules := make([]interface{}, 0, defaultRuleSetRuleLen)
-	for _, rule := range rs.Rules {
-		ifAttrs := make(map[string]interface{}, 2)
+	for ruleIdx, rule := range rs.Rules {
+		ifAttrs := make(map[string]interface{}, 4)
 		valueAttrs := make(map[string]interface{}, 2)
 		valueOverAttrs := make(map[string]interface{}, 2)
 		thenAttrs := make(map[string]interface{}, 3)
 
 		switch rule.Criteria {
 		case apiRuleSetAbsent:
+			var absentSeconds string
 			switch v := rule.Value.(type) {
 			case string:
-				valueAttrs[string(ruleSetAbsentAttr)] = v
+				absentSeconds = v
 			case float64:
 				d, _ := time.ParseDuration(fmt.Sprintf("%fs", v))
-				valueAttrs[string(ruleSetAbsentAttr)] = fmt.Sprintf("%d", int(d.Seconds()))
+				absentSeconds = fmt.Sprintf("%d", int(d.Seconds()))
 			default:
-				valueAttrs[string(ruleSetAbsentAttr)] = fmt.Sprintf("%v", v)
+				absentSeconds = fmt.Sprintf("%v", v)
+			}
+
+			// Both absent and absence map to the same API criteria, keep whichever
+			// form was configured.  Imports use absent.
+			absenceKey := fmt.Sprintf("%s.%d.%s.0.%s.#", ruleSetIfAttr, ruleIdx, ruleSetValueAttr, ruleSetAbsenceAttr)
+			if d.Get(absenceKey).(int) > 0 {
+				absenceAttrs := map[string]interface{}{
+					string(ruleSetWaitAttr): absentSeconds,
+				}
+				valueAttrs[string(ruleSetAbsenceAttr)] = []interface{}{absenceAttrs}
+			} else {
+				valueAttrs[string(ruleSetAbsentAttr)] = absentSeconds
 			}
 		case apiRuleSetChanged:
 			valueAttrs[string(ruleSetChangedAttr)] = "true"
@@ -472,10 +601,16 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 			return fmt.Errorf("PROVIDER BUG: Unsupported criteria %q", rule.Criteria)
 		}
 
+		if rule.Quantile != nil {
+			valueAttrs[string(ruleSetQuantileAttr)] = strconv.FormatFloat(*rule.Quantile, 'f', -1, 64)
+		}
+
 		thenAttrs[string(ruleSetAfterAttr)] = fmt.Sprintf("%d", 60*rule.Wait)
 		thenAttrs[string(ruleSetSeverityAttr)] = int(rule.Severity)
 		if int(rule.Severity) > 0 {
-			if contactGroups, ok := rs.ContactGroups[uint8(rule.Severity)]; ok {
+			if notifySeverities[uint8(rule.Severity)] {
+				thenAttrs[string(ruleSetNotifyAttr)] = make([]string, 0)
+			} else if contactGroups, ok := rs.ContactGroups[uint8(rule.Severity)]; ok {
 				sort.Strings(contactGroups)
 				thenAttrs[string(ruleSetNotifyAttr)] = contactGroups
 			} else {
@@ -486,7 +621,17 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 		thenSet = append(thenSet, thenAttrs)
 		ifAttrs[string(ruleSetThenAttr)] = thenSet
 
-		if rule.WindowingFunction != nil {
+		// A windowed rule is stored using the window/duration shorthand when it was
+		// configured that way, otherwise (including on import) it is stored as a
+		// value.over block.
+		windowKey := fmt.Sprintf("%s.%d.%s", ruleSetIfAttr, ruleIdx, ruleSetWindowAttr)
+		switch {
+		case rule.WindowingFunction != nil && *rule.WindowingFunction == defaultRuleSetWindowFunc && d.Get(windowKey).(string) != "":
+			ifAttrs[string(ruleSetWindowAttr)] = fmt.Sprintf("%d", rule.WindowingDuration)
+			if rule.WindowingMinDuration > 0 {
+				ifAttrs[string(ruleSetDurationAttr)] = fmt.Sprintf("%d", rule.WindowingMinDuration)
+			}
+		case rule.WindowingFunction != nil:
 			valueOverAttrs[string(ruleSetUsingAttr)] = *rule.WindowingFunction
 			// NOTE: Only save the window duration if a function was specified
 			valueOverAttrs[string(ruleSetLastAttr)] = fmt.Sprintf("%d", rule.WindowingDuration)
@@ -519,6 +664,26 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set(ruleSetMetricFilterAttr, rs.Filter)
 	_ = d.Set(ruleSetMetricTypeAttr, rs.MetricType)
 	_ = d.Set(ruleSetNotesAttr, indirect(rs.Notes))
+
+	if len(notifySeverities) > 0 {
+		notifyList := make([]interface{}, 0, len(notifySeverities))
+		for _, sev := range ruleSetNotifyOrder(d) {
+			contactGroups := make([]string, 0)
+			if cgs, ok := rs.ContactGroups[sev]; ok {
+				contactGroups = append(contactGroups, cgs...)
+				sort.Strings(contactGroups)
+			}
+
+			notifyList = append(notifyList, map[string]interface{}{
+				string(ruleSetContactGroupsAttr): contactGroups,
+				string(ruleSetSeverityAttr):      int(sev),
+			})
+		}
+
+		if err := d.Set(ruleSetNotifyAttr, notifyList); err != nil {
+			return fmt.Errorf("Unable to store rule set %q attribute: %w", ruleSetNotifyAttr, err)
+		}
+	}
 	j, err := rs.UserJSON.MarshalJSON()
 	rj := json.RawMessage(string(j))
 	log.Printf("%s", string(rj))
@@ -529,7 +694,8 @@ func ruleSetRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	_ = d.Set(ruleSetParentAttr, indirect(rs.Parent))
 
-	if err := d.Set(ruleSetTagsAttr, tagsToState(apiToTags(rs.Tags))); err != nil {
+	tags := ctxt.tagsWithoutDefaults(rs.Tags, derefStringList(flattenSet(d.Get(ruleSetTagsAttr).(*schema.Set))))
+	if err := d.Set(ruleSetTagsAttr, tagsToState(apiToTags(tags))); err != nil {
 		return fmt.Errorf("Unable to store rule set %q attribute: %w", ruleSetTagsAttr, err)
 	}
 
@@ -544,8 +710,22 @@ func ruleSetUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	rs.Tags = ctxt.tagsWithDefaults(rs.Tags)
+
+	if err := rs.ValidateWindows(ctxt); err != nil {
+		return err
+	}
+
+	if err := rs.ValidateQuantiles(ctxt); err != nil {
+		return err
+	}
+
 	rs.CID = d.Id()
 
+	if err := rs.ValidateParent(ctxt); err != nil {
+		return err
+	}
+
 	if err := rs.Update(ctxt); err != nil {
 		return fmt.Errorf("unable to update rule set %q: %w", d.Id(), err)
 	}
@@ -700,13 +880,52 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 				}
 			}
 
+			var windowSet bool
+			if v, found := ifAttrs[ruleSetWindowAttr]; found && v.(string) != "" {
+				i, err := strconv.Atoi(v.(string))
+				if err != nil {
+					return fmt.Errorf("unable to parse %q duration %q: %w", ruleSetWindowAttr, v.(string), err)
+				}
+
+				windowFunction := defaultRuleSetWindowFunc
+				rule.WindowingFunction = &windowFunction
+				rule.WindowingDuration = uint(i)
+				windowSet = true
+			}
+
+			if v, found := ifAttrs[ruleSetDurationAttr]; found && v.(string) != "" {
+				if !windowSet {
+					return fmt.Errorf("%s requires %s to be set", ruleSetDurationAttr, ruleSetWindowAttr)
+				}
+
+				i, err := strconv.Atoi(v.(string))
+				if err != nil {
+					return fmt.Errorf("unable to parse %q duration %q: %w", ruleSetDurationAttr, v.(string), err)
+				}
+				rule.WindowingMinDuration = uint(i)
+			}
+
 			if ruleSetValueListRaw, found := ifAttrs[ruleSetValueAttr]; found {
 				ruleSetValueList := ruleSetValueListRaw.([]interface{})
 				vr := ruleSetValueList[0]
 				valueAttrs := vr.(map[string]interface{})
 
+				var absenceWait string
+				if absenceListRaw, found := valueAttrs[ruleSetAbsenceAttr]; found {
+					for _, absenceRaw := range absenceListRaw.([]interface{}) {
+						absenceAttrs := absenceRaw.(map[string]interface{})
+						if v, found := absenceAttrs[ruleSetWaitAttr]; found {
+							absenceWait = v.(string)
+						}
+					}
+				}
+
+				if err := validateRuleSetAbsence(valueAttrs, absenceWait); err != nil {
+					return err
+				}
+
 				switch rs.MetricType {
-				case ruleSetMetricTypeNumeric:
+				case ruleSetMetricTypeNumeric, ruleSetMetricTypeHistogram:
 					if v, found := valueAttrs[ruleSetAbsentAttr]; found && v.(string) != "" {
 						s := v.(string)
 						if s != "" {
@@ -786,14 +1005,40 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 					return fmt.Errorf("PROVIDER BUG: unsupported rule set metric type: %q", rs.MetricType)
 				}
 
+				if v, found := valueAttrs[ruleSetQuantileAttr]; found && v.(string) != "" {
+					q, err := strconv.ParseFloat(v.(string), 64)
+					if err != nil {
+						return fmt.Errorf("unable to parse %q %q: %w", ruleSetQuantileAttr, v.(string), err)
+					}
+					rule.Quantile = &q
+				}
+
+				if absenceWait != "" {
+					d, err := time.ParseDuration(absenceWait + "s")
+					if err != nil {
+						return fmt.Errorf("unable to parse %q duration %q: %w", ruleSetWaitAttr, absenceWait, err)
+					}
+					rule.Criteria = apiRuleSetAbsent
+					rule.Value = float64(d.Seconds())
+				}
+
 				if ruleSetOverListRaw, found := valueAttrs[ruleSetOverAttr]; found {
 					overList := ruleSetOverListRaw.([]interface{})
+					if windowSet && len(overList) > 0 {
+						return fmt.Errorf("%s conflicts with %s.%s, only one may be set", ruleSetWindowAttr, ruleSetValueAttr, ruleSetOverAttr)
+					}
+
 					for _, overListRaw := range overList {
-						overAttrs := overListRaw.(map[string]interface{})
+						// an empty over block may come through as nil, it gets the
+						// defaults of the schema like any other over block
+						overAttrs, _ := overListRaw.(map[string]interface{})
+						if overAttrs == nil {
+							overAttrs = map[string]interface{}{ruleSetLastAttr: defaultRuleSetLast}
+						}
 
 						windowDuration := uint(0)
 						windowMinDuration := uint(0)
-						windowFunction := ""
+						windowFunction := defaultRuleSetWindowFunc
 
 						if v, found := overAttrs[ruleSetLastAttr]; found && v != "" {
 							i, err := strconv.Atoi(v.(string))
@@ -810,7 +1055,7 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 							windowMinDuration = uint(i)
 						}
 
-						if v, found := overAttrs[ruleSetUsingAttr]; found {
+						if v, found := overAttrs[ruleSetUsingAttr]; found && v.(string) != "" {
 							windowFunction = v.(string)
 						}
 
@@ -830,6 +1075,34 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
+	// A notify block replaces the contact groups for its severity.  Severities
+	// without a notify block keep the contact groups of their rules.
+	notifySeverities := make(map[uint8]bool)
+	if notifyListRaw, found := d.GetOk(ruleSetNotifyAttr); found {
+		for _, notifyListElem := range notifyListRaw.([]interface{}) {
+			notifyAttrs := notifyListElem.(map[string]interface{})
+
+			sev := uint8(notifyAttrs[string(ruleSetSeverityAttr)].(int))
+			if notifySeverities[sev] {
+				return fmt.Errorf("%s severity %d may only be specified once", ruleSetNotifyAttr, sev)
+			}
+			notifySeverities[sev] = true
+
+			if len(rs.ContactGroups[sev]) > 0 {
+				return fmt.Errorf("%s severity %d conflicts with the %s attribute of a rule with the same severity", ruleSetNotifyAttr, sev, ruleSetNotifyAttr)
+			}
+
+			contactGroups := make([]string, 0)
+			if cgsRaw, found := notifyAttrs[string(ruleSetContactGroupsAttr)]; found {
+				for _, cg := range cgsRaw.(*schema.Set).List() {
+					contactGroups = append(contactGroups, cg.(string))
+				}
+			}
+			sort.Strings(contactGroups)
+			rs.ContactGroups[sev] = contactGroups
+		}
+	}
+
 	if v, found := d.GetOk(ruleSetTagsAttr); found {
 		rs.Tags = derefStringList(flattenSet(v.(*schema.Set)))
 	}
@@ -861,6 +1134,339 @@ func (rs *circonusRuleSet) Update(ctxt *providerContext) error {
 	return nil
 }
 
+// ValidateWindows verifies that every rule's window is a multiple of the period
+// of the check the rule set is attached to.  The validation is skipped when the
+// period of the check can not be determined.
+func (rs *circonusRuleSet) ValidateWindows(ctxt *providerContext) error {
+	var windowed bool
+	for _, rule := range rs.Rules {
+		if rule.WindowingDuration > 0 {
+			windowed = true
+			break
+		}
+	}
+
+	if !windowed || rs.CheckCID == "" {
+		return nil
+	}
+
+	checkCID := rs.CheckCID
+	check, err := ctxt.client.FetchCheck(api.CIDType(&checkCID))
+	if err != nil {
+		log.Printf("[WARN] unable to fetch check %s, skipping rule set window validation: %v", rs.CheckCID, err)
+		return nil
+	}
+
+	bundleCID := check.CheckBundleCID
+	cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&bundleCID))
+	if err != nil {
+		log.Printf("[WARN] unable to fetch check bundle %s, skipping rule set window validation: %v", check.CheckBundleCID, err)
+		return nil
+	}
+
+	if cb.Period == 0 {
+		return nil
+	}
+
+	for i, rule := range rs.Rules {
+		if rule.WindowingDuration > 0 && rule.WindowingDuration%cb.Period != 0 {
+			return fmt.Errorf("rule %d for check ID %s has a window of %ds which is not a multiple of the check period (%ds)", i, rs.CheckCID, rule.WindowingDuration, cb.Period)
+		}
+	}
+
+	return nil
+}
+
+// ValidateQuantiles verifies that the metric of a rule set with quantile rules
+// is a histogram metric of its check.  The validation is skipped for metric
+// patterns, for metrics the check bundle does not list (yet), and when the
+// check bundle can not be fetched.
+func (rs *circonusRuleSet) ValidateQuantiles(ctxt *providerContext) error {
+	var quantiles bool
+	for _, rule := range rs.Rules {
+		if rule.Quantile != nil {
+			quantiles = true
+			break
+		}
+	}
+
+	if !quantiles || rs.CheckCID == "" || rs.MetricName == "" {
+		return nil
+	}
+
+	checkCID := rs.CheckCID
+	check, err := ctxt.client.FetchCheck(api.CIDType(&checkCID))
+	if err != nil {
+		log.Printf("[WARN] unable to fetch check %s, skipping rule set quantile validation: %v", rs.CheckCID, err)
+		return nil
+	}
+
+	bundleCID := check.CheckBundleCID
+	cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&bundleCID))
+	if err != nil {
+		log.Printf("[WARN] unable to fetch check bundle %s, skipping rule set quantile validation: %v", check.CheckBundleCID, err)
+		return nil
+	}
+
+	for _, m := range cb.Metrics {
+		if m.Name == rs.MetricName && m.Type != ruleSetMetricTypeHistogram {
+			return fmt.Errorf("metric %q of check ID %s is a %s metric, a %s requires a histogram metric", rs.MetricName, rs.CheckCID, m.Type, ruleSetQuantileAttr)
+		}
+	}
+
+	return nil
+}
+
+// ValidateParent verifies that the parent of the rule set exists and is not
+// the rule set itself.  A parent is either a rule set CID or a metric ID in the
+// form ${check_id}_${metric_name}, only the check of a metric ID is verified as
+// the metric may not have been collected yet.
+func (rs *circonusRuleSet) ValidateParent(ctxt *providerContext) error {
+	if rs.Parent == nil || *rs.Parent == "" {
+		return nil
+	}
+
+	parent := *rs.Parent
+	if rs.CID != "" && parent == rs.CID {
+		return fmt.Errorf("%s %q references the rule set itself", ruleSetParentAttr, parent)
+	}
+
+	if strings.HasPrefix(parent, config.RuleSetPrefix) {
+		if _, err := ctxt.client.FetchRuleSet(api.CIDType(&parent)); err != nil {
+			if api.IsNotFound(err) {
+				return fmt.Errorf("%s %q does not exist", ruleSetParentAttr, parent)
+			}
+			return fmt.Errorf("unable to fetch %s %q: %w", ruleSetParentAttr, parent, err)
+		}
+		return nil
+	}
+
+	checkID := strings.SplitN(parent, "_", 2)[0]
+	checkCID := config.CheckPrefix + "/" + checkID
+	if rs.CheckCID == checkCID && parent == checkID+"_"+rs.MetricName {
+		return fmt.Errorf("%s %q references the metric of the rule set itself", ruleSetParentAttr, parent)
+	}
+
+	if _, err := ctxt.client.FetchCheck(api.CIDType(&checkCID)); err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("%s %q references check %s which does not exist", ruleSetParentAttr, parent, checkCID)
+		}
+		return fmt.Errorf("unable to fetch check %s of %s %q: %w", checkCID, ruleSetParentAttr, parent, err)
+	}
+
+	return nil
+}
+
+// ruleSetNotifySeverities returns the severities managed by a notify block in
+// the current state.
+func ruleSetNotifySeverities(d *schema.ResourceData) map[uint8]bool {
+	severities := make(map[uint8]bool)
+	for _, sev := range ruleSetNotifyOrder(d) {
+		severities[sev] = true
+	}
+
+	return severities
+}
+
+// ruleSetNotifyOrder returns the severities of the notify blocks in the order
+// they appear in the current state.
+func ruleSetNotifyOrder(d *schema.ResourceData) []uint8 {
+	notifyList := d.Get(ruleSetNotifyAttr).([]interface{})
+	severities := make([]uint8, 0, len(notifyList))
+	for _, notifyListElem := range notifyList {
+		notifyAttrs := notifyListElem.(map[string]interface{})
+		severities = append(severities, uint8(notifyAttrs[string(ruleSetSeverityAttr)].(int)))
+	}
+
+	return severities
+}
+
+// ruleSetCustomizeDiff verifies that the contact groups referenced by notify
+// blocks exist.  Contact groups that are not yet known (e.g. created in the
+// same plan) are skipped.
+func ruleSetCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown(ruleSetIfAttr) && !d.Get(ruleSetAllowUnorderedAttr).(bool) {
+		if err := ruleSetValidateSeverityOrder(d.Get(ruleSetIfAttr).([]interface{})); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown(ruleSetNotifyAttr) {
+		return nil
+	}
+
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt == nil || ctxt.client == nil {
+		return nil
+	}
+
+	for _, notifyListElem := range d.Get(ruleSetNotifyAttr).([]interface{}) {
+		notifyAttrs, ok := notifyListElem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		cgsRaw, found := notifyAttrs[string(ruleSetContactGroupsAttr)]
+		if !found {
+			continue
+		}
+
+		for _, cgRaw := range cgsRaw.(*schema.Set).List() {
+			cid, ok := cgRaw.(string)
+			if !ok || cid == "" {
+				continue
+			}
+
+			if _, err := ctxt.client.FetchContactGroup(api.CIDType(&cid)); err != nil {
+				if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+					return fmt.Errorf("%s contact group %q does not exist", ruleSetNotifyAttr, cid)
+				}
+
+				return fmt.Errorf("unable to verify %s contact group %q: %w", ruleSetNotifyAttr, cid, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleSetThreshold is a min_value or max_value rule of an if block, see
+// ruleSetValidateSeverityOrder.
+type ruleSetThreshold struct {
+	rule      int
+	severity  int
+	threshold float64
+	raw       string
+}
+
+// ruleSetValidateSeverityOrder verifies that the thresholds of the if blocks
+// are ordered by severity, severity 1 being the most severe.  A max_value
+// must not be above the max_value of a more severe rule and a min_value must
+// not be below the min_value of a more severe rule, otherwise the less severe
+// rule can never fire first.  Only rules with the same criteria evaluated over
+// the same window are compared, thresholds which are not known yet are
+// skipped.
+func ruleSetValidateSeverityOrder(ifList []interface{}) error {
+	groups := make(map[string][]ruleSetThreshold)
+	var keys []string
+
+	for i, ifElem := range ifList {
+		ifAttrs, ok := ifElem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var severity int
+		if thenList, ok := ifAttrs[string(ruleSetThenAttr)].([]interface{}); ok && len(thenList) > 0 {
+			if thenAttrs, ok := thenList[0].(map[string]interface{}); ok {
+				severity, _ = thenAttrs[string(ruleSetSeverityAttr)].(int)
+			}
+		}
+
+		valueList, ok := ifAttrs[string(ruleSetValueAttr)].([]interface{})
+		if !ok || len(valueList) == 0 || severity == 0 {
+			continue
+		}
+		valueAttrs, ok := valueList[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		window := "raw"
+		if v, _ := ifAttrs[string(ruleSetWindowAttr)].(string); v != "" {
+			window = fmt.Sprintf("%s/%s", defaultRuleSetWindowFunc, v)
+		} else if overList, ok := valueAttrs[string(ruleSetOverAttr)].([]interface{}); ok && len(overList) > 0 {
+			overAttrs, _ := overList[0].(map[string]interface{})
+			using, _ := overAttrs[string(ruleSetUsingAttr)].(string)
+			last, _ := overAttrs[string(ruleSetLastAttr)].(string)
+			if using == "" {
+				using = defaultRuleSetWindowFunc
+			}
+			if last == "" {
+				last = defaultRuleSetLast
+			}
+			window = fmt.Sprintf("%s/%s", using, last)
+		}
+
+		// thresholds of different quantiles of a histogram are not comparable
+		if q, _ := valueAttrs[string(ruleSetQuantileAttr)].(string); q != "" {
+			window = fmt.Sprintf("%s %s %s", window, ruleSetQuantileAttr, q)
+		}
+
+		for _, attr := range []schemaAttr{ruleSetMaxValueAttr, ruleSetMinValueAttr} {
+			raw, _ := valueAttrs[string(attr)].(string)
+			if raw == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+
+			key := fmt.Sprintf("%s %s", attr, window)
+			if _, found := groups[key]; !found {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], ruleSetThreshold{rule: i, severity: severity, threshold: f, raw: raw})
+		}
+	}
+
+	for _, key := range keys {
+		isMax := strings.HasPrefix(key, string(ruleSetMaxValueAttr)+" ")
+		thresholds := groups[key]
+		for _, a := range thresholds {
+			for _, b := range thresholds {
+				if a.severity >= b.severity {
+					continue
+				}
+
+				// a is more severe than b
+				if isMax && b.threshold > a.threshold {
+					return fmt.Errorf("%s %d (severity %d) has a %s of %s above %s %d (severity %d, %s %s), set %s to allow this", ruleSetIfAttr, b.rule, b.severity, ruleSetMaxValueAttr, b.raw, ruleSetIfAttr, a.rule, a.severity, ruleSetMaxValueAttr, a.raw, ruleSetAllowUnorderedAttr)
+				}
+				if !isMax && b.threshold < a.threshold {
+					return fmt.Errorf("%s %d (severity %d) has a %s of %s below %s %d (severity %d, %s %s), set %s to allow this", ruleSetIfAttr, b.rule, b.severity, ruleSetMinValueAttr, b.raw, ruleSetIfAttr, a.rule, a.severity, ruleSetMinValueAttr, a.raw, ruleSetAllowUnorderedAttr)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRuleSetAbsence verifies that an absence rule (either absent or
+// absence.wait) has a positive duration and does not also carry another
+// predicate, such as a numeric threshold.
+func validateRuleSetAbsence(valueAttrs map[string]interface{}, absenceWait string) error {
+	var absent string
+	if v, found := valueAttrs[ruleSetAbsentAttr]; found {
+		absent = v.(string)
+	}
+
+	if absent == "" && absenceWait == "" {
+		return nil
+	}
+
+	if absent != "" && absenceWait != "" {
+		return fmt.Errorf("%s conflicts with %s, only one may be set", ruleSetAbsenceAttr, ruleSetAbsentAttr)
+	}
+
+	if absenceWait != "" {
+		i, err := strconv.Atoi(absenceWait)
+		if err != nil || i <= 0 {
+			return fmt.Errorf("%s.%s must be a positive number of seconds: %q", ruleSetAbsenceAttr, ruleSetWaitAttr, absenceWait)
+		}
+	}
+
+	for _, attr := range []schemaAttr{ruleSetMinValueAttr, ruleSetMaxValueAttr, ruleSetEqValueAttr, ruleSetNotEqValueAttr} {
+		if v, found := valueAttrs[string(attr)]; found && v.(string) != "" {
+			return fmt.Errorf("absence rules can not also carry a numeric threshold (%s)", attr)
+		}
+	}
+
+	return nil
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -896,6 +1502,21 @@ func (rs *circonusRuleSet) Validate() error {
 			return fmt.Errorf("rule %d for check ID %s cannot have a window_min_duration (atleast) greater than the window duration (last)", i, rs.CheckCID)
 		}
 
+		if rule.Quantile != nil {
+			if rs.MetricType != ruleSetMetricTypeHistogram {
+				return fmt.Errorf("rule %d for check ID %s has a %s but the rule set is not on a histogram metric.  Did you mean 'metric_type = \"histogram\"'?", i, rs.CheckCID, ruleSetQuantileAttr)
+			}
+			if !stringInSlice(rule.Criteria, []string{apiRuleSetMaxValue, apiRuleSetMinValue}) {
+				return fmt.Errorf("rule %d for check ID %s has a %s with the criteria '%s', only min_value and max_value are compared against a quantile", i, rs.CheckCID, ruleSetQuantileAttr, rule.Criteria)
+			}
+		}
+
+		if rs.MetricType == ruleSetMetricTypeHistogram {
+			if !stringInSlice(rule.Criteria, []string{apiRuleSetAbsent, apiRuleSetMaxValue, apiRuleSetMinValue}) {
+				return fmt.Errorf("rule %d for check ID %s is using the criteria '%s' which is incompatible with histogram metrics, only absent, min_value and max_value are supported", i, rs.CheckCID, rule.Criteria)
+			}
+			continue
+		}
 		if stringInSlice(rule.Criteria, []string{apiRuleSetMatch, apiRuleSetNotMatch, apiRuleSetContains, apiRuleSetNotContains}) {
 			if rs.MetricType != "text" {
 				return fmt.Errorf("rule %d for check ID %s is using a textual criteria '%s' but is flagged as a numeric type.  Did you mean 'metric_type = \"text\"'?", i, rs.CheckCID, rule.Criteria)
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -109,8 +111,9 @@ func resourceRuleSetGroup() *schema.Resource {
 							Required: true,
 						},
 						"rule_set": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp("rule_set", config.RuleSetCIDRegex),
 						},
 						"matching_severities": {
 							Type:     schema.TypeList,
@@ -142,6 +145,12 @@ func ruleSetGroupCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing rule set group schema during create: %w", err)
 	}
 
+	rsg.Tags = ctxt.tagsWithDefaults(rsg.Tags)
+
+	if err := rsg.ValidateRuleSets(ctxt); err != nil {
+		return err
+	}
+
 	if err := rsg.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating rule set group: %w", err)
 	}
@@ -175,6 +184,11 @@ func ruleSetGroupRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	rsg, err := loadRuleSetGroup(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -220,7 +234,7 @@ func ruleSetGroupRead(d *schema.ResourceData, meta interface{}) error {
 
 	tags := make([]interface{}, 0)
 	if len(rsg.Tags) > 0 {
-		for _, t := range rsg.Tags {
+		for _, t := range ctxt.tagsWithoutDefaults(rsg.Tags, derefStringList(flattenList(d.Get("tags").([]interface{})))) {
 			tags = append(tags, t)
 		}
 	}
@@ -237,6 +251,12 @@ func ruleSetGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	rs.Tags = ctxt.tagsWithDefaults(rs.Tags)
+
+	if err := rs.ValidateRuleSets(ctxt); err != nil {
+		return err
+	}
+
 	rs.CID = d.Id()
 
 	if err := rs.Update(ctxt); err != nil {
@@ -455,8 +475,13 @@ func (rsg *circonusRuleSetGroup) ParseConfig(d *schema.ResourceData) error {
 		sort.Sort(cs)
 
 		rsg.RuleSetConditions = make([]api.RuleSetGroupCondition, 0, len(x))
-		for _, m := range x {
+		for i, m := range x {
 			c := m.(map[string]interface{})
+			// the index of a condition is its position in the API's list of
+			// conditions, so the indexes must be 1..n without gaps.
+			if c["index"].(int) != i+1 {
+				return fmt.Errorf("condition indexes must be unique and sequential starting at 1, expected %d, found %d", i+1, c["index"].(int))
+			}
 			cond := api.RuleSetGroupCondition{}
 			sevs := c["matching_severities"].([]interface{})
 			cond.MatchingSeverities = make([]string, 0)
@@ -469,7 +494,7 @@ func (rsg *circonusRuleSetGroup) ParseConfig(d *schema.ResourceData) error {
 	}
 
 	if v, found := d.GetOk("tags"); found {
-		rsg.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+		rsg.Tags = derefStringList(flattenList(v.([]interface{})))
 	}
 
 	log.Printf("RuleSetGroup: %v\n", rsg)
@@ -504,5 +529,70 @@ func (rsg *circonusRuleSetGroup) Validate() error {
 
 	log.Printf("RuleSetGroup: %v\n", rsg)
 
+	if len(rsg.RuleSetConditions) == 0 {
+		return fmt.Errorf("rule set group %q must have at least one condition", rsg.Name)
+	}
+
+	for i, formula := range rsg.Formulas {
+		expression, _ := formula.Expression.(string)
+		if err := validateRuleSetGroupExpression(expression, len(rsg.RuleSetConditions)); err != nil {
+			return fmt.Errorf("Error with formula[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRuleSets verifies that every rule set referenced by a condition
+// exists.
+func (rsg *circonusRuleSetGroup) ValidateRuleSets(ctxt *providerContext) error {
+	for _, cond := range rsg.RuleSetConditions {
+		cid := cond.RuleSetCID
+		if _, err := ctxt.client.FetchRuleSet(api.CIDType(&cid)); err != nil {
+			if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+				return fmt.Errorf("condition rule set %q does not exist", cid)
+			}
+
+			return fmt.Errorf("unable to verify condition rule set %q: %w", cid, err)
+		}
+	}
+
+	return nil
+}
+
+var ruleSetGroupExpressionTokenRegexp = regexp.MustCompile(`[A-Za-z]+`)
+
+// validateRuleSetGroupExpression verifies that a formula expression only
+// references declared conditions.  An expression is either the number of
+// conditions that must match (e.g. "2") or a boolean expression of condition
+// letters (e.g. "A and (B or C)") where A is the condition with index 1.
+func validateRuleSetGroupExpression(expression string, numConditions int) error {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return fmt.Errorf("expression can not be empty")
+	}
+
+	if n, err := strconv.Atoi(expression); err == nil {
+		if n < 1 || n > numConditions {
+			return fmt.Errorf("expression %q must be between 1 and the number of conditions (%d)", expression, numConditions)
+		}
+		return nil
+	}
+
+	for _, token := range ruleSetGroupExpressionTokenRegexp.FindAllString(expression, -1) {
+		switch strings.ToLower(token) {
+		case "and", "or", "not":
+			continue
+		}
+
+		if len(token) != 1 || token[0] < 'A' || token[0] > 'Z' {
+			return fmt.Errorf("expression %q contains an invalid condition reference %q", expression, token)
+		}
+
+		if int(token[0]-'A') >= numConditions {
+			return fmt.Errorf("expression %q references undeclared condition %q", expression, token)
+		}
+	}
+
 	return nil
 }
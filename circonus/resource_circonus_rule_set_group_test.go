@@ -0,0 +1,180 @@
+package circonus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCirconusRuleSetGroup_basic(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+	groupName := fmt.Sprintf("Rule Set Group - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusRuleSetGroup,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetGroupConfigFmt, checkName, groupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set_group.icmp-group", "name", groupName),
+					resource.TestCheckResourceAttr("circonus_rule_set_group.icmp-group", "formula.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set_group.icmp-group", "condition.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateRuleSetGroupExpression(t *testing.T) {
+	tests := []struct {
+		expression    string
+		numConditions int
+		shouldFail    bool
+	}{
+		{"A and B", 2, false},
+		{"A and (B or not C)", 3, false},
+		{"2", 2, false},
+		{"3", 2, true},
+		{"0", 2, true},
+		{"A and C", 2, true},
+		{"A and b", 2, true},
+		{"", 1, true},
+	}
+
+	for _, test := range tests {
+		err := validateRuleSetGroupExpression(test.expression, test.numConditions)
+		if test.shouldFail && err == nil {
+			t.Errorf("expected %q with %d conditions to fail", test.expression, test.numConditions)
+		}
+		if !test.shouldFail && err != nil {
+			t.Errorf("expected %q with %d conditions to pass: %v", test.expression, test.numConditions, err)
+		}
+	}
+}
+
+func testAccCheckDestroyCirconusRuleSetGroup(s *terraform.State) error {
+	ctxt := testAccProvider.Meta().(*providerContext)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "circonus_rule_set_group" {
+			continue
+		}
+
+		cid := rs.Primary.ID
+		exists, err := checkRuleSetGroupExists(ctxt, api.CIDType(&cid))
+		switch {
+		case !exists:
+			// noop
+		case exists:
+			return fmt.Errorf("rule set group still exists after destroy")
+		case err != nil:
+			return fmt.Errorf("Error checking rule set group: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func checkRuleSetGroupExists(c *providerContext, ruleSetGroupCID api.CIDType) (bool, error) {
+	rsg, err := c.client.FetchRuleSetGroup(ruleSetGroupCID)
+	if err != nil {
+		if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if api.CIDType(&rsg.CID) == ruleSetGroupCID {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+const testAccCirconusRuleSetGroupConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  metric {
+    name = "minimum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_rule_set" "icmp-max" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "maximum"
+
+  if {
+    value {
+      max_value = 500
+    }
+
+    then {
+      severity = 3
+    }
+  }
+}
+
+resource "circonus_rule_set" "icmp-min" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "minimum"
+
+  if {
+    value {
+      max_value = 300
+    }
+
+    then {
+      severity = 3
+    }
+  }
+}
+
+resource "circonus_rule_set_group" "icmp-group" {
+  name = "%s"
+
+  formula {
+    expression = "A and B"
+    raise_severity = 2
+    wait = 0
+  }
+
+  condition {
+    index = 1
+    rule_set = circonus_rule_set.icmp-max.id
+    matching_severities = ["3"]
+  }
+
+  condition {
+    index = 2
+    rule_set = circonus_rule_set.icmp-min.id
+    matching_severities = ["3"]
+  }
+}
+`
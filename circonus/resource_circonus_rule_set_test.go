@@ -1,7 +1,9 @@
 package circonus
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -27,6 +29,7 @@ func TestAccCirconusRuleSet_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "metric_type", "numeric"),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "notes", "Simple check to create notifications based on ICMP performance."),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "link", "https://wiki.example.org/playbook/what-to-do-when-high-latency-strikes"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "allow_unordered_severities", "true"),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "if.#", "7"),
 
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "if.0.value.#", "1"),
@@ -90,6 +93,252 @@ func TestAccCirconusRuleSet_basic(t *testing.T) {
 	})
 }
 
+func TestAccCirconusRuleSet_window(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusRuleSet,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetWindowConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.#", "3"),
+
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.0.window", "300"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.0.duration", "120"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.0.value.0.over.#", "0"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.0.value.0.max_value", "400"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.0.then.0.severity", "1"),
+
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.1.window", ""),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.1.duration", ""),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.1.value.0.over.#", "0"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.1.value.0.max_value", "500"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.1.then.0.severity", "2"),
+
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.2.window", ""),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.2.value.0.over.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.2.value.0.over.0.last", "600"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.2.value.0.over.0.atleast", "0"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-window", "if.2.value.0.over.0.using", "average"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCirconusRuleSet_absence(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusRuleSet,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetAbsenceConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-absence", "if.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-absence", "if.0.value.0.absent", ""),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-absence", "if.0.value.0.absence.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-absence", "if.0.value.0.absence.0.wait", "900"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-absence", "if.0.then.0.severity", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCirconusRuleSet_histogram(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusRuleSet,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetHistogramConfigFmt, checkName, "max_value = 500"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-histogram", "metric_type", "histogram"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-histogram", "if.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-histogram", "if.0.value.0.max_value", "500"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetHistogramConfigFmt, checkName, "max_value = 500\n      quantile = 0.99"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-histogram", "if.0.value.0.max_value", "500"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-histogram", "if.0.value.0.quantile", "0.99"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusRuleSetHistogramConfigFmt, checkName, "eq_value = 500"),
+				ExpectError: regexp.MustCompile(`incompatible with histogram metrics`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusRuleSetHistogramConfigFmt, checkName, "max_value = 500\n      quantile = 1.5"),
+				ExpectError: regexp.MustCompile(`value must be between 0 and 1`),
+			},
+		},
+	})
+}
+
+func TestAccCirconusRuleSet_notify(t *testing.T) {
+	checkName := fmt.Sprintf("ICMP Ping check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusRuleSet,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusRuleSetNotifyConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "notify.#", "2"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "notify.0.severity", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "notify.0.contact_groups.#", "2"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "notify.1.severity", "2"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "notify.1.contact_groups.#", "1"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "if.0.then.0.notify.#", "0"),
+					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-notify", "if.1.then.0.notify.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestRuleSetValidateSeverityOrder(t *testing.T) {
+	rule := func(severity int, attr, threshold string, over map[string]interface{}, window string) interface{} {
+		value := map[string]interface{}{attr: threshold}
+		if over != nil {
+			value["over"] = []interface{}{over}
+		}
+		return map[string]interface{}{
+			"value":  []interface{}{value},
+			"window": window,
+			"then":   []interface{}{map[string]interface{}{"severity": severity}},
+		}
+	}
+
+	quantileRule := func(severity int, threshold, quantile string) interface{} {
+		r := rule(severity, "max_value", threshold, nil, "")
+		r.(map[string]interface{})["value"].([]interface{})[0].(map[string]interface{})["quantile"] = quantile
+		return r
+	}
+
+	tests := []struct {
+		name string
+		ifs  []interface{}
+		err  string
+	}{
+		{"max ordered", []interface{}{rule(1, "max_value", "500", nil, ""), rule(2, "max_value", "400", nil, "")}, ""},
+		{"max equal", []interface{}{rule(1, "max_value", "500", nil, ""), rule(2, "max_value", "500", nil, "")}, ""},
+		{"max unordered", []interface{}{rule(1, "max_value", "400", nil, ""), rule(2, "max_value", "500", nil, "")}, "if 1 (severity 2) has a max_value of 500 above if 0 (severity 1, max_value 400)"},
+		{"max unordered listed first", []interface{}{rule(3, "max_value", "500", nil, ""), rule(1, "max_value", "400", nil, "")}, "if 0 (severity 3) has a max_value of 500 above if 1 (severity 1, max_value 400)"},
+		{"min ordered", []interface{}{rule(1, "min_value", "2", nil, ""), rule(2, "min_value", "5", nil, "")}, ""},
+		{"min unordered", []interface{}{rule(1, "min_value", "5", nil, ""), rule(2, "min_value", "2", nil, "")}, "if 1 (severity 2) has a min_value of 2 below if 0 (severity 1, min_value 5)"},
+		{"min and max", []interface{}{rule(1, "min_value", "5", nil, ""), rule(2, "max_value", "500", nil, "")}, ""},
+		{"different windows", []interface{}{rule(1, "max_value", "400", nil, "300"), rule(2, "max_value", "500", map[string]interface{}{"last": "600"}, "")}, ""},
+		{"default over", []interface{}{rule(1, "max_value", "400", map[string]interface{}{"last": "300", "using": "average"}, ""), rule(2, "max_value", "500", nil, "300")}, "set allow_unordered_severities to allow this"},
+		{"no severity", []interface{}{rule(1, "max_value", "400", nil, ""), rule(0, "max_value", "500", nil, "")}, ""},
+		{"unknown threshold", []interface{}{rule(1, "max_value", "", nil, ""), rule(2, "max_value", "500", nil, "")}, ""},
+		{"different quantiles", []interface{}{quantileRule(1, "400", "0.5"), quantileRule(2, "500", "0.99")}, ""},
+		{"same quantile", []interface{}{quantileRule(1, "400", "0.99"), quantileRule(2, "500", "0.99")}, "set allow_unordered_severities to allow this"},
+	}
+
+	for _, test := range tests {
+		err := ruleSetValidateSeverityOrder(test.ifs)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: expected error containing %q", test.name, test.err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestRuleSetQuantile(t *testing.T) {
+	quantileRuleSet := func(criteria string, quantile float64) circonusRuleSet {
+		rs := newRuleSet()
+		rs.CheckCID = "/check/1234"
+		rs.MetricName = "latency"
+		rs.MetricType = ruleSetMetricTypeHistogram
+		rs.Rules = []api.RuleSetRule{{Criteria: criteria, Value: "500", Severity: 1, Quantile: &quantile}}
+		return rs
+	}
+
+	rs := quantileRuleSet(apiRuleSetMaxValue, 0.99)
+	if err := rs.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	b, err := json.Marshal(rs.Rules[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rule api.RuleSetRule
+	if err := json.Unmarshal(b, &rule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Quantile == nil || *rule.Quantile != 0.99 {
+		t.Errorf("expected the quantile to round trip, got %s", string(b))
+	}
+
+	rs = quantileRuleSet(apiRuleSetAbsent, 0.99)
+	if err := rs.Validate(); err == nil || !strings.Contains(err.Error(), "compared against a quantile") {
+		t.Errorf("expected an error for an absent rule with a quantile, got %v", err)
+	}
+
+	rs = quantileRuleSet(apiRuleSetMaxValue, 0.99)
+	rs.MetricType = ruleSetMetricTypeNumeric
+	if err := rs.Validate(); err == nil || !strings.Contains(err.Error(), "not on a histogram metric") {
+		t.Errorf("expected an error for a numeric rule set with a quantile, got %v", err)
+	}
+
+	for _, test := range []struct {
+		v     string
+		valid bool
+	}{{"0", true}, {"0.5", true}, {"1", true}, {"1.01", false}, {"-0.1", false}, {"p99", false}} {
+		_, errs := validateFloatString(ruleSetQuantileAttr, 0, 1)(test.v, string(ruleSetQuantileAttr))
+		if valid := len(errs) == 0; valid != test.valid {
+			t.Errorf("%s %q: expected valid %t, got %v", ruleSetQuantileAttr, test.v, test.valid, errs)
+		}
+	}
+}
+
+func TestRuleSetValidateParent(t *testing.T) {
+	parent := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		rs   api.RuleSet
+		err  string
+	}{
+		{"no parent", api.RuleSet{CID: "/rule_set/1", CheckCID: "/check/1", MetricName: "maximum"}, ""},
+		{"empty parent", api.RuleSet{CID: "/rule_set/1", CheckCID: "/check/1", MetricName: "maximum", Parent: parent("")}, ""},
+		{"self", api.RuleSet{CID: "/rule_set/1", CheckCID: "/check/1", MetricName: "maximum", Parent: parent("/rule_set/1")}, "references the rule set itself"},
+		{"own metric", api.RuleSet{CheckCID: "/check/1", MetricName: "maximum", Parent: parent("1_maximum")}, "references the metric of the rule set itself"},
+	}
+
+	for _, test := range tests {
+		rs := circonusRuleSet{RuleSet: test.rs}
+		err := rs.ValidateParent(nil)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: expected error containing %q", test.name, test.err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.err, err)
+		}
+	}
+}
+
 func testAccCheckDestroyCirconusRuleSet(s *terraform.State) error {
 	ctxt := testAccProvider.Meta().(*providerContext)
 
@@ -177,6 +426,7 @@ EOF
     }
   })
   link = "https://wiki.example.org/playbook/what-to-do-when-high-latency-strikes"
+  allow_unordered_severities = true
 
   if {
     value {
@@ -295,3 +545,209 @@ EOF
   tags = "${var.test_tags}"
 }
 `
+
+const testAccCirconusRuleSetWindowConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_rule_set" "icmp-latency-window" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "maximum"
+  allow_unordered_severities = true
+
+  if {
+    window = "300"
+    duration = "120"
+
+    value {
+      max_value = 400
+    }
+
+    then {
+      severity = 1
+    }
+  }
+
+  if {
+    value {
+      max_value = 500
+    }
+
+    then {
+      severity = 2
+    }
+  }
+
+  if {
+    value {
+      over {
+        last = "600"
+      }
+
+      max_value = 600
+    }
+
+    then {
+      severity = 3
+    }
+  }
+}
+`
+
+const testAccCirconusRuleSetAbsenceConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_rule_set" "icmp-latency-absence" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "maximum"
+
+  if {
+    value {
+      absence {
+        wait = "900"
+      }
+    }
+
+    then {
+      severity = 1
+    }
+  }
+}
+`
+
+const testAccCirconusRuleSetHistogramConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "histogram"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_rule_set" "icmp-latency-histogram" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "maximum"
+  metric_type = "histogram"
+
+  if {
+    value {
+      %s
+    }
+
+    then {
+      severity = 1
+    }
+  }
+}
+`
+
+const testAccCirconusRuleSetNotifyConfigFmt = `
+resource "circonus_check" "api_latency" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  icmp_ping {
+    count = 1
+  }
+
+  metric {
+    name = "maximum"
+    type = "numeric"
+  }
+
+  target = "api.circonus.com"
+}
+
+resource "circonus_rule_set" "icmp-latency-notify" {
+  check = "${circonus_check.api_latency.checks[0]}"
+  metric_name = "maximum"
+
+  notify {
+    severity = 1
+    contact_groups = [
+      "/contact_group/4680",
+      "/contact_group/4679"
+    ]
+  }
+
+  notify {
+    severity = 2
+    contact_groups = [ "/contact_group/4679" ]
+  }
+
+  if {
+    value {
+      max_value = 500
+    }
+
+    then {
+      severity = 1
+    }
+  }
+
+  if {
+    value {
+      max_value = 400
+    }
+
+    then {
+      severity = 2
+    }
+  }
+}
+`
@@ -11,31 +11,37 @@ import (
 const (
 	workspaceTitleAttr        = "title"
 	workspaceDescriptionAttr  = "description"
+	workspaceFavoriteAttr     = "favorite"
 	workspaceFavouriteAttr    = "favourite"
 	workspaceNotesAttr        = "notes"
 	workspaceTagsAttr         = "tags"
 	workspaceGraphsAttr       = "graphs"
 	workspaceSmartQueriesAttr = "smart_queries"
+	workspaceSmartQueryAttr   = "smart_query"
 
-	queryNameAttr  = "name"
-	queryQueryAttr = "query"
-	queryOrderAttr = "order"
+	queryGraphCountAttr = "graph_count"
+	queryNameAttr       = "name"
+	queryQueryAttr      = "query"
+	queryOrderAttr      = "order"
 )
 
 var worksheetDescriptions = attrDescrs{
 	workspaceTitleAttr:        "",
 	workspaceDescriptionAttr:  "",
+	workspaceFavoriteAttr:     "Mark (star) the worksheet as a favorite",
 	workspaceFavouriteAttr:    "",
 	workspaceNotesAttr:        "",
 	workspaceTagsAttr:         "",
 	workspaceGraphsAttr:       "",
 	workspaceSmartQueriesAttr: "",
+	workspaceSmartQueryAttr:   "Tag based queries including graphs in the worksheet, in order",
 }
 
 var worksheetSmartQueryDescriptions = attrDescrs{
-	queryNameAttr:  "",
-	queryQueryAttr: "",
-	queryOrderAttr: "",
+	queryGraphCountAttr: "The number of graphs the query currently resolves to",
+	queryNameAttr:       "",
+	queryQueryAttr:      "",
+	queryOrderAttr:      "",
 }
 
 func resourceWorksheet() *schema.Resource {
@@ -61,10 +67,18 @@ func resourceWorksheet() *schema.Resource {
 				StateFunc: suppressWhitespace,
 			},
 
+			workspaceFavoriteAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       defaultWorkspaceFavorite,
+				ConflictsWith: []string{workspaceFavouriteAttr},
+			},
+
 			workspaceFavouriteAttr: {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  defaultWorkspaceFavourite,
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Deprecated:    "use favorite instead",
+				ConflictsWith: []string{workspaceFavoriteAttr},
 			},
 
 			workspaceNotesAttr: {
@@ -81,8 +95,10 @@ func resourceWorksheet() *schema.Resource {
 			},
 
 			workspaceSmartQueriesAttr: {
-				Type:     schema.TypeSet,
-				Optional: true,
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Deprecated:    "use smart_query blocks instead, smart_queries does not preserve the order of the queries",
+				ConflictsWith: []string{workspaceSmartQueryAttr},
 				Elem: &schema.Resource{
 					Schema: convertToHelperSchema(worksheetSmartQueryDescriptions, map[schemaAttr]*schema.Schema{
 						queryNameAttr: {
@@ -103,6 +119,36 @@ func resourceWorksheet() *schema.Resource {
 					}),
 				},
 			},
+			workspaceSmartQueryAttr: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{workspaceSmartQueriesAttr},
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(worksheetSmartQueryDescriptions, map[schemaAttr]*schema.Schema{
+						queryGraphCountAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						queryNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(queryNameAttr, `\S`),
+						},
+						queryQueryAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(queryQueryAttr, `\S`),
+						},
+						queryOrderAttr: {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					}),
+				},
+			},
 			workspaceTagsAttr: tagMakeConfigSchema(workspaceTagsAttr),
 		}),
 	}
@@ -115,6 +161,8 @@ func worksheetCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error parsing graph schema during create: %w", err)
 	}
 
+	g.Tags = ctxt.tagsWithDefaults(g.Tags)
+
 	if err := g.Create(ctxt); err != nil {
 		return fmt.Errorf("error creating graph: %w", err)
 	}
@@ -130,6 +178,11 @@ func worksheetRead(d *schema.ResourceData, meta interface{}) error {
 	cid := d.Id()
 	w, err := loadWorksheet(ctxt, api.CIDType(&cid))
 	if err != nil {
+		if api.IsNotFound(err) {
+			// the resource was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -137,37 +190,81 @@ func worksheetRead(d *schema.ResourceData, meta interface{}) error {
 
 	_ = d.Set(workspaceTitleAttr, w.Title)
 	_ = d.Set(workspaceDescriptionAttr, w.Description)
-	_ = d.Set(workspaceFavouriteAttr, w.Favorite)
+	// the deprecated favourite attribute is only stored when it is the one in
+	// use, otherwise favorite would perpetually differ from its default
+	if d.Get(workspaceFavouriteAttr).(bool) {
+		_ = d.Set(workspaceFavouriteAttr, w.Favorite)
+	} else {
+		_ = d.Set(workspaceFavoriteAttr, w.Favorite)
+	}
 	_ = d.Set(workspaceNotesAttr, w.Notes)
 
 	if err := d.Set(workspaceGraphsAttr, worksheetGraphsToState(apiToWorksheetGraphs(w.Graphs))); err != nil {
 		return fmt.Errorf("Unable to store workspace %q attribute: %w", workspaceTagsAttr, err)
 	}
 
-	if err := d.Set(workspaceTagsAttr, tagsToState(apiToTags(w.Tags))); err != nil {
+	tags := ctxt.tagsWithoutDefaults(w.Tags, derefStringList(flattenSet(d.Get(workspaceTagsAttr).(*schema.Set))))
+	if err := d.Set(workspaceTagsAttr, tagsToState(apiToTags(tags))); err != nil {
 		return fmt.Errorf("Unable to store workspace %q attribute: %w", workspaceTagsAttr, err)
 	}
 
-	var smartQueries []map[string]interface{}
+	// smart queries are stored in the deprecated smart_queries attribute only
+	// when it is the one in use, otherwise (e.g. on import) in smart_query
+	if _, ok := d.GetOk(workspaceSmartQueriesAttr); ok {
+		var smartQueries []map[string]interface{}
 
-	for _, query := range w.SmartQueries {
+		for _, query := range w.SmartQueries {
+
+			newQuery := map[string]interface{}{
+				"name":  query.Name,
+				"query": query.Query,
+				"order": query.Order,
+			}
 
-		newQuery := map[string]interface{}{
-			"name":  query.Name,
-			"query": query.Query,
-			"order": query.Order,
+			smartQueries = append(smartQueries, newQuery)
 		}
 
-		smartQueries = append(smartQueries, newQuery)
+		if err := d.Set(workspaceSmartQueriesAttr, smartQueries); err != nil {
+			return fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceSmartQueriesAttr, err)
+		}
+
+		return nil
+	}
+
+	smartQueries := make([]interface{}, 0, len(w.SmartQueries))
+	for _, query := range w.SmartQueries {
+		graphCount, err := worksheetSmartQueryGraphCount(ctxt, query.Query)
+		if err != nil {
+			return fmt.Errorf("unable to resolve worksheet %s %q: %w", workspaceSmartQueryAttr, query.Name, err)
+		}
+
+		smartQueries = append(smartQueries, map[string]interface{}{
+			string(queryGraphCountAttr): graphCount,
+			string(queryNameAttr):       query.Name,
+			string(queryQueryAttr):      query.Query,
+			string(queryOrderAttr):      query.Order,
+		})
 	}
 
-	if err := d.Set(workspaceSmartQueriesAttr, smartQueries); err != nil {
-		return fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceSmartQueriesAttr, err)
+	if err := d.Set(workspaceSmartQueryAttr, smartQueries); err != nil {
+		return fmt.Errorf("unable to store worksheet %q attribute: %w", workspaceSmartQueryAttr, err)
 	}
 
 	return nil
 }
 
+// worksheetSmartQueryGraphCount returns the number of graphs a smart query
+// currently resolves to
+func worksheetSmartQueryGraphCount(ctxt *providerContext, query string) (int, error) {
+	q := api.SearchQueryType(query)
+	graphs, err := ctxt.client.SearchGraphs(&q, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(*graphs), nil
+}
+
 func worksheetExists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	ctxt := meta.(*providerContext)
 
@@ -195,6 +292,8 @@ func worksheetUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	w.Tags = ctxt.tagsWithDefaults(w.Tags)
+
 	w.CID = d.Id()
 	if err := w.Update(ctxt); err != nil {
 		return fmt.Errorf("unable to update worksheet %q: %w", d.Id(), err)
@@ -249,16 +348,14 @@ func loadWorksheet(ctxt *providerContext, cid api.CIDType) (circonusWorksheet, e
 
 func (w *circonusWorksheet) ParseConfig(d *schema.ResourceData) error {
 	w.Title = d.Get(workspaceTitleAttr).(string)
+	w.Favorite = d.Get(workspaceFavoriteAttr).(bool) || d.Get(workspaceFavouriteAttr).(bool)
 
-	if v, ok := d.GetOk(workspaceDescriptionAttr); ok {
-		desc := v.(string)
-		w.Description = &desc
-	}
+	// always send description and notes, an empty string clears them
+	desc := d.Get(workspaceDescriptionAttr).(string)
+	w.Description = &desc
 
-	if v, ok := d.GetOk(workspaceNotesAttr); ok {
-		notes := v.(string)
-		w.Notes = &notes
-	}
+	notes := d.Get(workspaceNotesAttr).(string)
+	w.Notes = &notes
 
 	if v, found := d.GetOk(workspaceTagsAttr); found {
 		w.Tags = derefStringList(flattenSet(v.(*schema.Set)))
@@ -294,7 +391,7 @@ func (w *circonusWorksheet) ParseConfig(d *schema.ResourceData) error {
 
 			if v, found := queryAttrs[queryOrderAttr]; found {
 				orderList := v.(*schema.Set).List()
-				query.Order = make([]string, len(orderList))
+				query.Order = make([]string, 0, len(orderList))
 				for _, s := range orderList {
 					query.Order = append(query.Order, s.(string))
 				}
@@ -305,6 +402,29 @@ func (w *circonusWorksheet) ParseConfig(d *schema.ResourceData) error {
 		w.SmartQueries = smaryQueries
 	}
 
+	if v, found := d.GetOk(workspaceSmartQueryAttr); found {
+		queriesList := v.([]interface{})
+		smartQueries := make([]api.WorksheetSmartQuery, 0, len(queriesList))
+
+		for _, queryListRaw := range queriesList {
+			queryAttrs := newInterfaceMap(queryListRaw)
+
+			query := api.WorksheetSmartQuery{
+				Name:  queryAttrs[queryNameAttr].(string),
+				Query: queryAttrs[queryQueryAttr].(string),
+				Order: []string{},
+			}
+
+			if v, found := queryAttrs[queryOrderAttr]; found {
+				query.Order = derefStringList(flattenList(v.([]interface{})))
+			}
+
+			smartQueries = append(smartQueries, query)
+		}
+
+		w.SmartQueries = smartQueries
+	}
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -24,9 +25,37 @@ func TestAccCirconusWorksheet_basic(t *testing.T) {
 			{
 				Config: fmt.Sprintf(testAccCirconusWorksheetConfigFmt, checkName, graphName, worksheetName),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("circonus_worksheet.test", "favourite"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "favorite", "false"),
 				),
 			},
+			{
+				Config: fmt.Sprintf(testAccCirconusWorksheetMetadataConfigFmt, worksheetName, "true", "Terraform test notes"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "favorite", "true"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "notes", "Terraform test notes"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "description", "Terraform test worksheet"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusWorksheetMetadataConfigFmt, worksheetName, "false", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "favorite", "false"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "notes", ""),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCirconusWorksheetSmartQueryConfigFmt, worksheetName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "smart_query.#", "2"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "smart_query.0.name", "Unit tests"),
+					resource.TestCheckResourceAttr("circonus_worksheet.test", "smart_query.1.name", "Terraform"),
+					resource.TestCheckResourceAttrSet("circonus_worksheet.test", "smart_query.0.graph_count"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccCirconusWorksheetEmptySmartQueryConfigFmt, worksheetName),
+				ExpectError: regexp.MustCompile(`Invalid query specified`),
+			},
 		},
 	})
 }
@@ -155,3 +184,39 @@ resource "circonus_worksheet" "test" {
   ]
 }
 `
+
+const testAccCirconusWorksheetMetadataConfigFmt = `
+resource "circonus_worksheet" "test" {
+  title = "%s"
+  description = "Terraform test worksheet"
+  favorite = %s
+  notes = "%s"
+}
+`
+
+const testAccCirconusWorksheetSmartQueryConfigFmt = `
+resource "circonus_worksheet" "test" {
+  title = "%s"
+
+  smart_query {
+    name = "Unit tests"
+    query = "(tags:lifecycle:unittest)"
+  }
+
+  smart_query {
+    name = "Terraform"
+    query = "(tags:author:terraform)"
+  }
+}
+`
+
+const testAccCirconusWorksheetEmptySmartQueryConfigFmt = `
+resource "circonus_worksheet" "test" {
+  title = "%s"
+
+  smart_query {
+    name = "Empty"
+    query = " "
+  }
+}
+`
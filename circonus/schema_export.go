@@ -0,0 +1,103 @@
+package circonus
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// schemaExport is the machine readable form of the provider schema written by
+// ExportSchemaJSON.
+type schemaExport struct {
+	ProviderVersion string                          `json:"provider_version"`
+	Provider        map[string]schemaExportAttr     `json:"provider"`
+	Resources       map[string]schemaExportResource `json:"resources"`
+	DataSources     map[string]schemaExportResource `json:"data_sources"`
+}
+
+type schemaExportResource struct {
+	Attributes map[string]schemaExportAttr `json:"attributes"`
+}
+
+type schemaExportAttr struct {
+	Type          string                      `json:"type"`
+	Description   string                      `json:"description,omitempty"`
+	Required      bool                        `json:"required,omitempty"`
+	Optional      bool                        `json:"optional,omitempty"`
+	Computed      bool                        `json:"computed,omitempty"`
+	ForceNew      bool                        `json:"force_new,omitempty"`
+	Sensitive     bool                        `json:"sensitive,omitempty"`
+	ConflictsWith []string                    `json:"conflicts_with,omitempty"`
+	MinItems      int                         `json:"min_items,omitempty"`
+	MaxItems      int                         `json:"max_items,omitempty"`
+	ElementType   string                      `json:"element_type,omitempty"`
+	Attributes    map[string]schemaExportAttr `json:"attributes,omitempty"`
+}
+
+// ExportSchemaJSON returns the schema of the provider, its resources, and its
+// data sources as indented JSON.  The schema is read from the same
+// schema.Resource definitions Terraform uses, version is the version of the
+// provider included in the output.
+func ExportSchemaJSON(version string) ([]byte, error) {
+	p := Provider()
+
+	export := schemaExport{
+		ProviderVersion: version,
+		Provider:        schemaExportAttrs(p.Schema),
+		Resources:       make(map[string]schemaExportResource, len(p.ResourcesMap)),
+		DataSources:     make(map[string]schemaExportResource, len(p.DataSourcesMap)),
+	}
+
+	for name, r := range p.ResourcesMap {
+		export.Resources[name] = schemaExportResource{Attributes: schemaExportAttrs(r.Schema)}
+	}
+
+	for name, r := range p.DataSourcesMap {
+		export.DataSources[name] = schemaExportResource{Attributes: schemaExportAttrs(r.Schema)}
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+func schemaExportAttrs(s map[string]*schema.Schema) map[string]schemaExportAttr {
+	attrs := make(map[string]schemaExportAttr, len(s))
+	for name, attr := range s {
+		a := schemaExportAttr{
+			Type:        schemaExportType(attr.Type),
+			Description: attr.Description,
+			Required:    attr.Required,
+			Optional:    attr.Optional,
+			Computed:    attr.Computed,
+			ForceNew:    attr.ForceNew,
+			Sensitive:   attr.Sensitive,
+			MinItems:    attr.MinItems,
+			MaxItems:    attr.MaxItems,
+		}
+
+		if len(attr.ConflictsWith) > 0 {
+			a.ConflictsWith = append([]string(nil), attr.ConflictsWith...)
+			sort.Strings(a.ConflictsWith)
+		}
+
+		switch elem := attr.Elem.(type) {
+		case *schema.Resource:
+			a.Attributes = schemaExportAttrs(elem.Schema)
+		case *schema.Schema:
+			a.ElementType = schemaExportType(elem.Type)
+		case schema.ValueType:
+			a.ElementType = schemaExportType(elem)
+		}
+
+		attrs[name] = a
+	}
+
+	return attrs
+}
+
+// schemaExportType returns the name of a schema type without its Type prefix,
+// e.g. "string" for schema.TypeString.
+func schemaExportType(t schema.ValueType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "Type"))
+}
@@ -0,0 +1,49 @@
+package circonus
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportSchemaJSON(t *testing.T) {
+	out, err := ExportSchemaJSON("1.2.3")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var export schemaExport
+	if err := json.Unmarshal(out, &export); err != nil {
+		t.Fatalf("unable to parse exported schema: %s", err)
+	}
+
+	if export.ProviderVersion != "1.2.3" {
+		t.Errorf("expected provider_version %q, got %q", "1.2.3", export.ProviderVersion)
+	}
+
+	if len(export.Resources) != len(Provider().ResourcesMap) {
+		t.Errorf("expected %d resources, got %d", len(Provider().ResourcesMap), len(export.Resources))
+	}
+
+	if len(export.DataSources) != len(Provider().DataSourcesMap) {
+		t.Errorf("expected %d data sources, got %d", len(Provider().DataSourcesMap), len(export.DataSources))
+	}
+
+	name := export.Resources["circonus_graph"].Attributes[string(graphNameAttr)]
+	if name.Type != "string" || !name.Required {
+		t.Errorf("expected circonus_graph.name to be a required string, got %+v", name)
+	}
+
+	left := export.Resources["circonus_graph"].Attributes[string(graphLeftAttr)]
+	if left.Type != "map" || left.ElementType != "string" || !left.Optional {
+		t.Errorf("expected circonus_graph.left to be an optional map of strings, got %+v", left)
+	}
+
+	metric := export.Resources["circonus_graph"].Attributes[string(graphMetricAttr)]
+	if metric.Type != "list" || metric.Attributes[string(graphMetricColorAttr)].Type != "string" {
+		t.Errorf("expected circonus_graph.metric to be a list block with a color attribute, got %+v", metric)
+	}
+
+	if _, ok := export.Provider[providerAPIURLAttr]; !ok {
+		t.Errorf("expected provider attribute %q", providerAPIURLAttr)
+	}
+}
@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -22,6 +23,21 @@ func tagMakeConfigSchema(tagAttrName schemaAttr) *schema.Schema {
 	}
 }
 
+// tagMakeMapConfigSchema returns the schema of the map form of the tags, e.g.
+// { env = "prod" } for the "env:prod" tag.  The map form conflicts with the list
+// form in tagsAttrName.
+func tagMakeMapConfigSchema(tagsAttrName schemaAttr) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeMap,
+		Optional:      true,
+		ConflictsWith: []string{string(tagsAttrName)},
+		ValidateFunc:  validateTagsMap,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
 func (t circonusTag) Category() string {
 	tagInfo := strings.SplitN(string(t), ":", 2)
 	switch len(tagInfo) {
@@ -56,6 +72,54 @@ func tagsToState(tags circonusTags) *schema.Set {
 	return tagSet
 }
 
+// tagsMapToAPI returns the category:value tags of the map form of the tags,
+// sorted.
+func tagsMapToAPI(m map[string]interface{}) []string {
+	tags := make([]string, 0, len(m))
+	for category, value := range m {
+		tags = append(tags, category+":"+value.(string))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// tagsToMap returns the map form of the tags as returned by the API.  A tag
+// without a value maps to an empty value.
+func tagsToMap(apiTags []string) map[string]interface{} {
+	m := make(map[string]interface{}, len(apiTags))
+	for _, t := range apiTags {
+		tagInfo := strings.SplitN(t, ":", 2)
+		if len(tagInfo) == 1 {
+			m[tagInfo[0]] = ""
+			continue
+		}
+		m[tagInfo[0]] = tagInfo[1]
+	}
+	return m
+}
+
+// tagsFromConfig returns the tags configured in either the list form,
+// tagsAttr, or the map form, tagsMapAttr.
+func tagsFromConfig(d *schema.ResourceData, tagsAttr, tagsMapAttr schemaAttr) []string {
+	if v, found := d.GetOk(string(tagsMapAttr)); found {
+		return tagsMapToAPI(v.(map[string]interface{}))
+	}
+
+	if v, found := d.GetOk(string(tagsAttr)); found {
+		return derefStringList(flattenSet(v.(*schema.Set)))
+	}
+
+	return nil
+}
+
+// tagsMapDeclared returns true when the tags are managed in the map form,
+// tagsMapAttr.  Imported resources have no tags in the state and use the list
+// form.
+func tagsMapDeclared(d *schema.ResourceData, tagsMapAttr schemaAttr) bool {
+	_, found := d.GetOk(string(tagsMapAttr))
+	return found
+}
+
 func apiToTags(apiTags []string) circonusTags {
 	tags := make(circonusTags, 0, len(apiTags))
 	for _, v := range apiTags {
@@ -63,3 +127,97 @@ func apiToTags(apiTags []string) circonusTags {
 	}
 	return tags
 }
+
+// tagsMergeInherited merges the inherited tags into the tags of a metric.  An
+// inherited tag is only added when the metric has no tag of the same category,
+// the metric's own tags take precedence.
+func tagsMergeInherited(own, inherited []string) []string {
+	categories := make(map[string]struct{}, len(own))
+	seen := make(map[string]struct{}, len(own)+len(inherited))
+	merged := make([]string, 0, len(own)+len(inherited))
+	for _, t := range own {
+		categories[circonusTag(t).Category()] = struct{}{}
+		if _, found := seen[strings.ToLower(t)]; !found {
+			seen[strings.ToLower(t)] = struct{}{}
+			merged = append(merged, t)
+		}
+	}
+
+	for _, t := range inherited {
+		if _, found := categories[circonusTag(t).Category()]; found {
+			continue
+		}
+		if _, found := seen[strings.ToLower(t)]; !found {
+			seen[strings.ToLower(t)] = struct{}{}
+			merged = append(merged, t)
+		}
+	}
+
+	sort.Strings(merged)
+	return merged
+}
+
+// tagsStripInherited removes the inherited tags from the tags of a metric as
+// returned by the API, keeping the tags which were configured on the metric.
+func tagsStripInherited(tags, inherited, configured []string) []string {
+	keep := make(map[string]struct{}, len(configured))
+	for _, t := range configured {
+		keep[strings.ToLower(t)] = struct{}{}
+	}
+
+	strip := make(map[string]struct{}, len(inherited))
+	for _, t := range inherited {
+		if _, found := keep[strings.ToLower(t)]; !found {
+			strip[strings.ToLower(t)] = struct{}{}
+		}
+	}
+
+	stripped := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if _, found := strip[strings.ToLower(t)]; !found {
+			stripped = append(stripped, t)
+		}
+	}
+
+	return stripped
+}
+
+// tagsWithDefaults returns the tags with the provider's default_tags appended.
+// A default tag is only added when the tags have no tag of the same category,
+// the resource's own tags take precedence.  The order of the own tags is kept.
+func (ctxt *providerContext) tagsWithDefaults(tags []string) []string {
+	if len(ctxt.defaultTags) == 0 {
+		return tags
+	}
+
+	categories := make(map[string]struct{}, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		categories[circonusTag(t).Category()] = struct{}{}
+		seen[strings.ToLower(t)] = struct{}{}
+	}
+
+	merged := append(make([]string, 0, len(tags)+len(ctxt.defaultTags)), tags...)
+	for _, t := range ctxt.defaultTags {
+		if _, found := categories[circonusTag(t).Category()]; found {
+			continue
+		}
+		if _, found := seen[strings.ToLower(t)]; !found {
+			seen[strings.ToLower(t)] = struct{}{}
+			merged = append(merged, t)
+		}
+	}
+
+	return merged
+}
+
+// tagsWithoutDefaults removes the provider's default_tags from the tags as
+// returned by the API, keeping the default tags which were also configured on
+// the resource.
+func (ctxt *providerContext) tagsWithoutDefaults(apiTags, configured []string) []string {
+	if len(ctxt.defaultTags) == 0 {
+		return apiTags
+	}
+
+	return tagsStripInherited(apiTags, ctxt.defaultTags, configured)
+}
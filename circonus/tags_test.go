@@ -0,0 +1,114 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_TagsMergeInherited(t *testing.T) {
+	tests := []struct {
+		own       []string
+		inherited []string
+		merged    []string
+	}{
+		{nil, []string{"env:prod"}, []string{"env:prod"}},
+		{[]string{"unit:bytes"}, []string{"env:prod"}, []string{"env:prod", "unit:bytes"}},
+		{[]string{"env:dev"}, []string{"env:prod", "app:web"}, []string{"app:web", "env:dev"}},
+		{[]string{"env:prod"}, []string{"env:prod"}, []string{"env:prod"}},
+		{[]string{"unit:bytes"}, nil, []string{"unit:bytes"}},
+	}
+
+	for _, test := range tests {
+		merged := tagsMergeInherited(test.own, test.inherited)
+		if !reflect.DeepEqual(merged, test.merged) {
+			t.Errorf("merging %q into %q: expected %q, got %q", test.inherited, test.own, test.merged, merged)
+		}
+	}
+}
+
+func Test_TagsStripInherited(t *testing.T) {
+	tests := []struct {
+		tags       []string
+		inherited  []string
+		configured []string
+		stripped   []string
+	}{
+		{[]string{"env:prod", "unit:bytes"}, []string{"env:prod"}, []string{"unit:bytes"}, []string{"unit:bytes"}},
+		{[]string{"env:prod", "unit:bytes"}, []string{"env:prod"}, []string{"env:prod", "unit:bytes"}, []string{"env:prod", "unit:bytes"}},
+		{[]string{"env:dev"}, []string{"env:prod"}, []string{"env:dev"}, []string{"env:dev"}},
+		{[]string{"env:prod"}, []string{"env:prod"}, nil, []string{}},
+	}
+
+	for _, test := range tests {
+		stripped := tagsStripInherited(test.tags, test.inherited, test.configured)
+		if !reflect.DeepEqual(stripped, test.stripped) {
+			t.Errorf("stripping %q from %q: expected %q, got %q", test.inherited, test.tags, test.stripped, stripped)
+		}
+	}
+}
+
+func Test_TagsMap(t *testing.T) {
+	m := map[string]interface{}{"env": "prod", "app": "web", "url": "http://example.com"}
+	tags := tagsMapToAPI(m)
+	expected := []string{"app:web", "env:prod", "url:http://example.com"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %q, got %q", expected, tags)
+	}
+
+	if back := tagsToMap(tags); !reflect.DeepEqual(back, m) {
+		t.Errorf("expected %v, got %v", m, back)
+	}
+
+	if back := tagsToMap([]string{"standalone"}); !reflect.DeepEqual(back, map[string]interface{}{"standalone": ""}) {
+		t.Errorf("expected a tag without value to map to an empty value, got %v", back)
+	}
+
+	tests := []struct {
+		m     map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"env": "prod"}, true},
+		{map[string]interface{}{"env": "prod:eu"}, true},
+		{map[string]interface{}{"env:prod": "eu"}, false},
+		{map[string]interface{}{"": "prod"}, false},
+	}
+
+	for _, test := range tests {
+		_, errs := validateTagsMap(test.m, "tags_map")
+		if valid := len(errs) == 0; valid != test.valid {
+			t.Errorf("%v: expected valid %t, got %v", test.m, test.valid, errs)
+		}
+	}
+}
+
+func Test_TagsDefaults(t *testing.T) {
+	ctxt := &providerContext{defaultTags: []string{"env:prod", "team:ops"}}
+
+	tests := []struct {
+		own    []string
+		merged []string
+		read   []string
+	}{
+		{nil, []string{"env:prod", "team:ops"}, []string{}},
+		{[]string{"unit:bytes"}, []string{"unit:bytes", "env:prod", "team:ops"}, []string{"unit:bytes"}},
+		{[]string{"env:dev"}, []string{"env:dev", "team:ops"}, []string{"env:dev"}},
+		{[]string{"team:ops", "app:web"}, []string{"team:ops", "app:web", "env:prod"}, []string{"team:ops", "app:web"}},
+	}
+
+	for _, test := range tests {
+		merged := ctxt.tagsWithDefaults(test.own)
+		if !reflect.DeepEqual(merged, test.merged) {
+			t.Errorf("merging defaults into %q: expected %q, got %q", test.own, test.merged, merged)
+		}
+
+		read := ctxt.tagsWithoutDefaults(merged, test.own)
+		if !reflect.DeepEqual(read, test.read) {
+			t.Errorf("stripping defaults from %q: expected %q, got %q", merged, test.read, read)
+		}
+	}
+
+	own := []string{"env:dev"}
+	if tags := (&providerContext{}).tagsWithDefaults(own); !reflect.DeepEqual(tags, own) {
+		t.Errorf("without defaults: expected %q, got %q", own, tags)
+	}
+}
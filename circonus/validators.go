@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -187,6 +188,24 @@ func validateFloatMax(attrName schemaAttr, max float64) func(v interface{}, key
 	}
 }
 
+// validateFloatString verifies that a string attribute is a number between min
+// and max, inclusive.
+func validateFloatString(attrName schemaAttr, min, max float64) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		f, err := strconv.ParseFloat(v.(string), 64)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): not a number", attrName, v.(string)))
+			return warnings, errors
+		}
+
+		if f < min || f > max {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): value must be between %g and %g", attrName, v.(string), min, max))
+		}
+
+		return warnings, errors
+	}
+}
+
 // validateFuncs takes a list of functions and runs them in serial until either
 // a warning or error is returned from the first validation function argument.
 func validateFuncs(fns ...func(v interface{}, key string) (warnings []string, errors []error)) func(v interface{}, key string) (warnings []string, errors []error) {
@@ -236,6 +255,32 @@ func validateGraphAxisOptions(v interface{}, key string) (warnings []string, err
 		}
 	}
 
+	if v, ok := axisOptionsMap[string(graphAxisLogarithmicAttr)]; ok {
+		if i, err := strconv.Atoi(v.(string)); err != nil || i < 0 {
+			errors = append(errors, fmt.Errorf("Invalid %s %s specified (%q): must be 0 or a positive integer", key, graphAxisLogarithmicAttr, v))
+		}
+	}
+
+	bounds := make(map[schemaAttr]float64, 2)
+	for _, attr := range []schemaAttr{graphAxisMinAttr, graphAxisMaxAttr} {
+		v, ok := axisOptionsMap[string(attr)]
+		if !ok || v.(string) == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v.(string), 64)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s %s specified (%q): must be a number", key, attr, v))
+			continue
+		}
+		bounds[attr] = f
+	}
+
+	min, minFound := bounds[graphAxisMinAttr]
+	max, maxFound := bounds[graphAxisMaxAttr]
+	if minFound && maxFound && min >= max {
+		errors = append(errors, fmt.Errorf("Invalid %s axis bounds: %s (%v) must be less than %s (%v)", key, graphAxisMinAttr, min, graphAxisMaxAttr, max))
+	}
+
 	return warnings, errors
 }
 
@@ -259,6 +304,16 @@ func validateIntMax(attrName schemaAttr, max int) func(v interface{}, key string
 	}
 }
 
+func validateJSONPath(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		if _, err := parseJSONPath(v.(string)); err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): %v", attrName, v.(string), err))
+		}
+
+		return warnings, errors
+	}
+}
+
 func validateMetricType(v interface{}, key string) (warnings []string, errors []error) {
 	value := v.(string)
 	switch value {
@@ -282,6 +337,18 @@ func validateRegexp(attrName schemaAttr, reString string) func(v interface{}, ke
 	}
 }
 
+// validateTagsMap verifies the categories of the map form of the tags, the
+// category of a tag ends at its first colon.
+func validateTagsMap(v interface{}, key string) (warnings []string, errors []error) {
+	for category := range v.(map[string]interface{}) {
+		if category == "" || strings.ContainsRune(category, ':') {
+			errors = append(errors, fmt.Errorf("Invalid %s category %q: must not be empty or contain a colon", key, category))
+		}
+	}
+
+	return warnings, errors
+}
+
 func validateTag(v interface{}, key string) (warnings []string, errors []error) {
 	tag := v.(string)
 	if !strings.ContainsRune(tag, ':') {
@@ -311,6 +378,7 @@ const (
 	urlWithoutPath
 	urlWithoutPort
 	urlWithoutSchema
+	urlWithPort
 )
 
 const urlBasicCheck urlParseFlags = 0
@@ -351,6 +419,10 @@ func validateHTTPURL(attrName schemaAttr, checkFlags urlParseFlags) func(v inter
 			}
 		}
 
+		if checkFlags&urlWithPort != 0 && err == nil && u.Port() == "" {
+			errors = append(errors, fmt.Errorf("Port is missing from URL %q (HINT: %s://%s:2609%s)", v.(string), u.Scheme, u.Host, u.Path))
+		}
+
 		return warnings, errors
 	}
 }
@@ -1,12 +1,34 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/circonus-labs/terraform-provider-circonus/circonus"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
+// version is the version of the provider, set at build time with
+// -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
+	var schemaJSON bool
+	flag.BoolVar(&schemaJSON, "schema-json", false, "print the schema of the provider as JSON and exit")
+	flag.Parse()
+
+	if schemaJSON {
+		out, err := circonus.ExportSchemaJSON(version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to export schema: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() *schema.Provider {
 			return circonus.Provider()